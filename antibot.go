@@ -0,0 +1,112 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the honeypot and timestamp fields of the anti-bot protection
+const (
+	honeypotField  = "_goform_website"
+	timestampField = "_goform_ts"
+)
+
+// honeypotHTML visually hidden input, people do not see it and bots fill it
+const honeypotHTML = `<div style="position: absolute; left: -10000px; width: 1px; height: 1px; overflow: hidden;" aria-hidden="true">` +
+	`<label for="` + honeypotField + `">Leave this field empty</label>` +
+	`<input type="text" name="` + honeypotField + `" id="` + honeypotField + `" value="" tabindex="-1" autocomplete="off">` +
+	`</div>`
+
+// antiBotMaxAge default maximum time between the render and the submission
+const antiBotMaxAge = 24 * time.Hour
+
+// ErrSpam the submission filled the honeypot, was sent too fast or has no valid timestamp
+var ErrSpam = errors.New("goform: submission rejected as spam")
+
+// antiBotConfig structure, honeypot and timing protection of the form
+type antiBotConfig struct {
+	secret   []byte
+	minDelay time.Duration
+	maxAge   time.Duration
+}
+
+// EnableAntiBot add a visually hidden honeypot field and a render timestamp, signed with the
+// secret and bound to the form name, to the rendered form. VerifyAntiBot rejects the submissions
+// that fill the honeypot or arrive less than minDelay after the form was rendered.
+func (f *Form) EnableAntiBot(secret []byte, minDelay time.Duration) {
+	f.antiBot.secret = secret
+	f.antiBot.minDelay = minDelay
+}
+
+// SetAntiBotMaxAge set the maximum time between the render and the submission, 24 hours by default,
+// older timestamps are rejected by VerifyAntiBot.
+func (f *Form) SetAntiBotMaxAge(maxAge time.Duration) {
+	f.antiBot.maxAge = maxAge
+}
+
+// VerifyAntiBot returns ErrSpam if the submission of the request filled the honeypot,
+// arrived faster than the minimum delay or later than the maximum age, or the timestamp
+// is missing, altered or from other form.
+func (f *Form) VerifyAntiBot(r *http.Request) error {
+
+	if r.FormValue(honeypotField) != "" {
+		return fmt.Errorf("%w: honeypot filled", ErrSpam)
+	}
+
+	text, err := verifyValue(f.antiBot.secret, r.FormValue(timestampField))
+	if err != nil || len(f.antiBot.secret) == 0 {
+		return fmt.Errorf("%w: invalid timestamp", ErrSpam)
+	}
+
+	// The form name may contain the separator, the timestamp can not
+	separator := strings.LastIndex(text, "|")
+	rendered, err := strconv.ParseInt(text[separator+1:], 10, 64)
+	if separator < 0 || err != nil || text[:separator] != f.Name {
+		return fmt.Errorf("%w: invalid timestamp", ErrSpam)
+	}
+
+	maxAge := f.antiBot.maxAge
+	if maxAge <= 0 {
+		maxAge = antiBotMaxAge
+	}
+
+	elapsed := time.Since(time.UnixMilli(rendered))
+	if elapsed < f.antiBot.minDelay {
+		return fmt.Errorf("%w: submitted too fast", ErrSpam)
+	}
+	if elapsed > maxAge {
+		return fmt.Errorf("%w: expired timestamp", ErrSpam)
+	}
+
+	return nil
+}
+
+// renderAntiBot write the honeypot and the signed timestamp if the form has anti-bot enabled
+func (f *Form) renderAntiBot(w io.Writer) error {
+
+	if len(f.antiBot.secret) == 0 {
+		return nil
+	}
+
+	if _, err := io.WriteString(w, honeypotHTML); err != nil {
+		return err
+	}
+
+	tmpl, err := f.template("hidden")
+	if err != nil {
+		return err
+	}
+
+	field := EmptyField()
+	field.FieldType = "hidden"
+	field.Name = timestampField
+	field.ID = timestampField
+	field.Value = signValue(f.antiBot.secret, f.Name+"|"+strconv.FormatInt(time.Now().UnixMilli(), 10))
+
+	return tmpl.Execute(w, field)
+}
@@ -0,0 +1,71 @@
+package goform
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var antiBotSecret = []byte("anti-bot secret")
+
+// timestampAt returns the signed timestamp of the form rendered at the time
+func timestampAt(name string, rendered time.Time) string {
+	return signValue(antiBotSecret, name+"|"+strconv.FormatInt(rendered.UnixMilli(), 10))
+}
+
+func TestVerifyAntiBot(t *testing.T) {
+
+	form := Create("contact", "POST", "/")
+	form.EnableAntiBot(antiBotSecret, 3*time.Second)
+	form.SetAntiBotMaxAge(time.Hour)
+
+	human := timestampAt("contact", time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name   string
+		values url.Values
+		valid  bool
+	}{
+		{"human", url.Values{timestampField: {human}}, true},
+		{"honeypot filled", url.Values{timestampField: {human}, honeypotField: {"http://spam.example"}}, false},
+		{"too fast", url.Values{timestampField: {timestampAt("contact", time.Now())}}, false},
+		{"expired", url.Values{timestampField: {timestampAt("contact", time.Now().Add(-2*time.Hour))}}, false},
+		{"other form", url.Values{timestampField: {timestampAt("newsletter", time.Now().Add(-time.Minute))}}, false},
+		{"other secret", url.Values{timestampField: {signValue([]byte("other"), "contact|1")}}, false},
+		{"altered", url.Values{timestampField: {human + "x"}}, false},
+		{"without timestamp", url.Values{}, false},
+	}
+
+	for _, test := range tests {
+		err := form.VerifyAntiBot(postValues("/", test.values))
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, ErrSpam) {
+			t.Errorf("%s: error %v, want ErrSpam", test.name, err)
+		}
+	}
+}
+
+func TestAntiBotRender(t *testing.T) {
+
+	form := Create("contact", "POST", "/")
+	form.EnableAntiBot(antiBotSecret, 0)
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, `name="`+honeypotField+`"`) || !strings.Contains(html, `name="`+timestampField+`"`) {
+		t.Fatalf("form without honeypot or timestamp:\n%s", html)
+	}
+
+	match := regexp.MustCompile(`name="_goform_ts"[^>]*value="([^"]+)"`).FindStringSubmatch(html)
+	if err := form.VerifyAntiBot(postValues("/", url.Values{timestampField: {match[1]}})); err != nil {
+		t.Errorf("rendered timestamp: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnsafeAttribute the attribute or the CSS declaration is invalid or can run scripts
+var ErrUnsafeAttribute = errors.New("goform: unsafe attribute")
+
+var (
+	// attributeName valid names of the attributes added to the elements
+	attributeName = regexp.MustCompile(`^[a-zA-Z_:][-a-zA-Z0-9_:.]*$`)
+	// cssProperty valid names of the CSS properties
+	cssProperty = regexp.MustCompile(`^-?[a-zA-Z][-a-zA-Z0-9]*$`)
+)
+
+// urlAttributes attributes with a URL value, javascript: and vbscript: URLs are rejected
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"poster":     true,
+	"cite":       true,
+	"background": true,
+	"xlink:href": true,
+}
+
+// blockedAttributes attributes that can not be added, style has its own validation in AddCSS
+var blockedAttributes = map[string]bool{
+	"style":  true,
+	"srcdoc": true,
+}
+
+// blockedCSS properties that can run scripts
+var blockedCSS = map[string]bool{
+	"behavior":     true,
+	"-moz-binding": true,
+}
+
+// unsafeCSS fragments of CSS values that can run scripts or break the style attribute
+var unsafeCSS = []string{"expression(", "javascript:", "vbscript:", "-moz-binding", "behavior:", "/*", "\\", ";", "{", "}", "<", ">"}
+
+// checkAttribute returns ErrUnsafeAttribute if the name is not a valid attribute name, it is an
+// event handler or a blocked attribute, or the value of a URL attribute is a script
+func checkAttribute(name string, value string) error {
+
+	lower := strings.ToLower(name)
+
+	switch {
+	case !attributeName.MatchString(name):
+		return fmt.Errorf("%w: invalid name %q", ErrUnsafeAttribute, name)
+	case strings.HasPrefix(lower, "on"):
+		return fmt.Errorf("%w: event handler %q", ErrUnsafeAttribute, name)
+	case blockedAttributes[lower]:
+		return fmt.Errorf("%w: %q is not allowed", ErrUnsafeAttribute, name)
+	case urlAttributes[lower] && scriptURL(value):
+		return fmt.Errorf("%w: script URL in %q", ErrUnsafeAttribute, name)
+	}
+
+	return nil
+}
+
+// checkCSS returns ErrUnsafeAttribute if the property name is not valid or the value can run scripts
+func checkCSS(property string, value string) error {
+
+	if !cssProperty.MatchString(property) || blockedCSS[strings.ToLower(property)] {
+		return fmt.Errorf("%w: invalid CSS property %q", ErrUnsafeAttribute, property)
+	}
+
+	normalized := strings.ToLower(strings.Join(strings.Fields(value), ""))
+	for _, fragment := range unsafeCSS {
+		if strings.Contains(normalized, fragment) {
+			return fmt.Errorf("%w: invalid CSS value for %q", ErrUnsafeAttribute, property)
+		}
+	}
+
+	return nil
+}
+
+// scriptURL returns true if the URL runs a script (javascript: or vbscript:), spaces and
+// control characters are ignored like the browsers do
+func scriptURL(value string) bool {
+
+	normalized := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(value))
+
+	return strings.HasPrefix(normalized, "javascript:") || strings.HasPrefix(normalized, "vbscript:")
+}
+
+// renderAttributes returns the attributes as ` key="value"` pairs sorted by key, with the values
+// escaped, unsafe attributes are skipped.
+func renderAttributes(attributes map[string]string) template.HTMLAttr {
+
+	keys := make([]string, 0, len(attributes))
+	for key, value := range attributes {
+		if checkAttribute(key, value) == nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	text := ""
+	for _, key := range keys {
+		text += " " + key + `="` + template.HTMLEscapeString(attributes[key]) + `"`
+	}
+
+	return template.HTMLAttr(text)
+}
+
+// Attrs returns the Attributes of the option ready to be placed in the option tag.
+func (option OptionItem) Attrs() template.HTMLAttr {
+	return renderAttributes(option.Attributes)
+}
+
+// renderStyle returns the CSS declarations sorted by property, unsafe declarations are skipped
+func renderStyle(css map[string]string) template.CSS {
+
+	keys := make([]string, 0, len(css))
+	for key, value := range css {
+		if checkCSS(key, value) == nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	declarations := make([]string, 0, len(keys))
+	for _, key := range keys {
+		declarations = append(declarations, key+": "+css[key]+";")
+	}
+
+	return template.CSS(strings.Join(declarations, " "))
+}
+
+// ParamsAttr returns the Params of the field ready to be placed in the input tag.
+func (field Field) ParamsAttr() template.HTMLAttr {
+	return renderAttributes(field.Params)
+}
+
+// Style returns the CSS of the field ready to be placed in the style attribute.
+func (field Field) Style() template.CSS {
+	return renderStyle(field.CSS)
+}
+
+// Style returns the CSS of the form ready to be placed in the style attribute.
+func (f *Form) Style() template.CSS {
+	return renderStyle(f.CSS)
+}
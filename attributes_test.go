@@ -0,0 +1,124 @@
+package goform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckAttribute(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"maxlength", "10", true},
+		{"data-x", "anything", true},
+		{"aria-label", "Name", true},
+		{"xlink:href", "https://example.com", true},
+		{"href", "/relative/path", true},
+		{"href", "https://example.com/javascript:", true},
+		{"onclick", "alert(1)", false},
+		{"ONCLICK", "alert(1)", false},
+		{"onMouseOver", "alert(1)", false},
+		{"style", "color: red", false},
+		{"STYLE", "color: red", false},
+		{"srcdoc", "<script>alert(1)</script>", false},
+		{"", "x", false},
+		{"a b", "x", false},
+		{`x"onclick="alert(1)`, "x", false},
+		{"x>", "x", false},
+		{"1x", "x", false},
+		{"href", "javascript:alert(1)", false},
+		{"src", "JaVaScRiPt:alert(1)", false},
+		{"formaction", " JaVa\tscript:alert(1)", false},
+		{"action", "java\nscript:alert(1)", false},
+		{"poster", "\x00javascript:alert(1)", false},
+		{"href", "vbscript:msgbox(1)", false},
+		{"HREF", "javascript:alert(1)", false},
+	}
+
+	for _, tt := range tests {
+		err := checkAttribute(tt.name, tt.value)
+		if tt.valid && err != nil {
+			t.Errorf("checkAttribute(%q, %q) error %v, want nil", tt.name, tt.value, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrUnsafeAttribute) {
+			t.Errorf("checkAttribute(%q, %q) error %v, want ErrUnsafeAttribute", tt.name, tt.value, err)
+		}
+	}
+}
+
+func TestCheckCSS(t *testing.T) {
+
+	tests := []struct {
+		property string
+		value    string
+		valid    bool
+	}{
+		{"color", "red", true},
+		{"font-family", `"Open Sans", sans-serif`, true},
+		{"font-family", "'Open Sans'", true},
+		{"-webkit-transition", "all 0.3s", true},
+		{"background", "url(https://example.com/a.png)", true},
+		{"width", "calc(100% - 2px)", true},
+		{"width", "expression(alert(1))", false},
+		{"width", "EXPRESSION (alert(1))", false},
+		{"background", "url(javascript:alert(1))", false},
+		{"background", "url( Java Script:alert(1))", false},
+		{"background", "url(vbscript:msgbox(1))", false},
+		{"color", `red\3b`, false},
+		{"color", "red; background: url(x)", false},
+		{"color", "red /* comment */", false},
+		{"color", "red} body {color: blue", false},
+		{"color", "red</style>", false},
+		{"-moz-binding", "url(x)", false},
+		{"color", "-moz-binding: url(x)", false},
+		{"behavior", "url(x.htc)", false},
+		{"BEHAVIOR", "url(x.htc)", false},
+		{"color", "behavior: url(x.htc)", false},
+		{"", "red", false},
+		{"color:", "red", false},
+		{"col or", "red", false},
+	}
+
+	for _, tt := range tests {
+		err := checkCSS(tt.property, tt.value)
+		if tt.valid && err != nil {
+			t.Errorf("checkCSS(%q, %q) error %v, want nil", tt.property, tt.value, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrUnsafeAttribute) {
+			t.Errorf("checkCSS(%q, %q) error %v, want ErrUnsafeAttribute", tt.property, tt.value, err)
+		}
+	}
+}
+
+func TestUnsafeAttributesNotRendered(t *testing.T) {
+
+	form := Create("profile", "POST", "/profile")
+	form.NewElement("text", "name", "")
+	form.AddParams("name", "maxlength", "20")
+	form.AddParams("name", "data-note", `"><script>alert(1)</script>`)
+	form.AddParams("name", "onfocus", "alert(1)")
+	form.AddCSS("name", "color", "red")
+	form.AddCSS("name", "width", "expression(alert(1))")
+
+	if errs := form.Errors(); len(errs) != 2 || !errors.Is(errs[0].Err, ErrUnsafeAttribute) || !errors.Is(errs[1].Err, ErrUnsafeAttribute) {
+		t.Errorf("errors %v, want the unsafe attribute and CSS", errs)
+	}
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, unsafe := range []string{"onfocus", "expression", "<script>"} {
+		if strings.Contains(html, unsafe) {
+			t.Errorf("%q rendered:\n%s", unsafe, html)
+		}
+	}
+	if !strings.Contains(html, `maxlength="20"`) {
+		t.Errorf("maxlength not rendered:\n%s", html)
+	}
+}
@@ -0,0 +1,123 @@
+package goform
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// bindTypes input types that receive the submitted value
+var bindTypes = map[string]bool{
+	"text":     true,
+	"password": true,
+	"select":   true,
+	"radio":    true,
+	"textarea": true,
+	"hidden":   true,
+}
+
+// multiTypes input types that receive all the submitted values of its name
+var multiTypes = map[string]bool{
+	"multiselect":   true,
+	"checkboxgroup": true,
+}
+
+func init() {
+	for _, inputType := range html5Types {
+		bindTypes[inputType] = true
+	}
+}
+
+// Bind parse the submitted request and populate the form fields with its values,
+// returns ErrInvalidSignature if the value of a signedhidden element was altered
+// (the first one in the order of the elements).
+func (f *Form) Bind(r *http.Request) error {
+
+	if err := f.parseRequest(r); err != nil {
+		return err
+	}
+
+	if items := f.populate(r.Form); len(items) > 0 {
+		return items[0].Err
+	}
+
+	return nil
+}
+
+// parseRequest parse the submitted values, including multipart forms
+func (f *Form) parseRequest(r *http.Request) error {
+
+	if f.MultipartFormData == "enabled" {
+		return r.ParseMultipartForm(32 << 20)
+	}
+
+	return r.ParseForm()
+}
+
+// Populate set the values of the fields from the submitted values,
+// checkboxes are checked when its value (or "on" if empty) was submitted.
+// Altered values of signedhidden elements are registered in the form log.
+func (f *Form) Populate(values url.Values) {
+	f.errors = append(f.errors, f.populate(values)...)
+}
+
+// populate set the values of the fields, returns the errors of the signedhidden elements
+func (f *Form) populate(values url.Values) []ErrorItem {
+
+	var items []ErrorItem
+
+	// Sorted, so the errors are reported in the order of the elements
+	for _, field := range f.SortElements() {
+
+		submitted, ok := values[field.Name]
+
+		switch {
+		case field.FieldType == "repeater":
+			field.Rows = ParseRepeater(values, field.Name)
+		case multiTypes[field.FieldType]:
+			field.Values = append([]string{}, submitted...)
+		case field.FieldType == "checkbox":
+			field.Checked = checkboxChecked(field, submitted)
+		case field.FieldType == "signedhidden":
+			value, err := f.openValue(field.Name, values.Get(field.Name))
+			if err != nil {
+				items = append(items, openError(field.Name, err))
+				continue
+			}
+			field.Value = value
+		case bindTypes[field.FieldType]:
+			if !ok {
+				continue
+			}
+			field.Value = values.Get(field.Name)
+		default:
+			continue
+		}
+
+		f.Elements[field.Name] = field
+	}
+
+	return items
+}
+
+// checkboxChecked returns true if the value of the checkbox (or "on" if empty) was submitted
+func checkboxChecked(field Field, submitted []string) bool {
+	for _, value := range submitted {
+		if value == field.Value || (field.Value == "" && value == "on") {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the selected values of a multiselect or checkboxgroup,
+// after Bind or Populate these are the submitted values.
+func (f *Form) Values(fieldName string) []string {
+	return append([]string{}, f.Elements[fieldName].Values...)
+}
+
+// SetChecked set/change the checked state of a checkbox.
+func (f *Form) SetChecked(fieldName string, checked bool) {
+	field := f.Elements[fieldName]
+	field.Checked = checked
+	f.Elements[fieldName] = field
+}
@@ -0,0 +1,144 @@
+package goform
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// bindForm returns a form with an element of each bound type
+func bindForm() *Form {
+
+	form := Create("profile", "POST", "/profile")
+	form.NewElement("text", "name", "default")
+	form.NewElement("password", "password", "")
+	form.NewElement("textarea", "bio", "")
+	form.NewElement("hidden", "id", "7")
+	form.NewElement("select", "city", "")
+	form.SetOptions("city", []OptionItem{{Key: "bcn", Value: "Barcelona"}, {Key: "mad", Value: "Madrid"}})
+	form.NewElement("radio", "size", "")
+	form.SetOptions("size", []OptionItem{{Key: "s", Value: "Small"}, {Key: "l", Value: "Large"}})
+	form.NewElement("checkbox", "news", "yes")
+	form.NewElement("checkbox", "terms", "")
+
+	return form
+}
+
+func TestPopulate(t *testing.T) {
+
+	form := bindForm()
+	form.SetChecked("news", true)
+
+	form.Populate(url.Values{
+		"name":     {"Jane", "ignored"},
+		"password": {"secret"},
+		"bio":      {"<b>Hi</b>"},
+		"city":     {"mad"},
+		"size":     {"l"},
+		"terms":    {"on"},
+	})
+
+	want := map[string]string{"name": "Jane", "password": "secret", "bio": "<b>Hi</b>", "id": "7", "city": "mad", "size": "l"}
+	for name, value := range want {
+		if got := form.Elements[name].Value; got != value {
+			t.Errorf("value of %s %q, want %q", name, got, value)
+		}
+	}
+
+	// Unchecked checkboxes are not submitted
+	if form.Elements["news"].Checked {
+		t.Error("news checked, want unchecked")
+	}
+	if !form.Elements["terms"].Checked {
+		t.Error("terms unchecked, want checked")
+	}
+}
+
+func TestPopulateCheckbox(t *testing.T) {
+
+	tests := []struct {
+		value     string
+		submitted []string
+		checked   bool
+	}{
+		{"yes", []string{"yes"}, true},
+		{"yes", []string{"other", "yes"}, true},
+		{"yes", []string{"on"}, false},
+		{"yes", []string{"other"}, false},
+		{"yes", nil, false},
+		{"", []string{"on"}, true},
+		{"", []string{""}, true},
+		{"", nil, false},
+	}
+
+	for _, tt := range tests {
+
+		form := Create("signup", "POST", "/signup")
+		form.NewElement("checkbox", "terms", tt.value)
+		form.AddRule("terms", Required("Accept the terms"))
+
+		values := url.Values{}
+		if tt.submitted != nil {
+			values["terms"] = tt.submitted
+		}
+
+		form.Populate(values)
+		if form.Elements["terms"].Checked != tt.checked {
+			t.Errorf("value %q, submitted %q: checked %v, want %v", tt.value, tt.submitted, !tt.checked, tt.checked)
+		}
+
+		// Validate agrees with Populate
+		if valid := len(form.ValidateValues(values)) == 0; valid != tt.checked {
+			t.Errorf("value %q, submitted %q: required rule valid %v, want %v", tt.value, tt.submitted, valid, tt.checked)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+
+	form := bindForm()
+	r := postValues("/profile", url.Values{"name": {"Jane"}, "city": {"bcn"}, "news": {"yes"}})
+
+	if err := form.Bind(r); err != nil {
+		t.Fatal(err)
+	}
+
+	if form.Elements["name"].Value != "Jane" || form.Elements["city"].Value != "bcn" || !form.Elements["news"].Checked {
+		t.Errorf("bound elements %+v", form.Elements)
+	}
+
+	// Not submitted fields keep its value
+	if form.Elements["id"].Value != "7" || form.Elements["size"].Value != "" {
+		t.Errorf("not submitted elements changed: id %q, size %q", form.Elements["id"].Value, form.Elements["size"].Value)
+	}
+}
+
+func TestBindMultipart(t *testing.T) {
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", "Jane")
+	writer.WriteField("city", "mad")
+	writer.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/profile", body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+
+	form := bindForm()
+	form.SetMultipartFormData("enabled")
+
+	if err := form.Bind(r); err != nil {
+		t.Fatal(err)
+	}
+	if form.Elements["name"].Value != "Jane" || form.Elements["city"].Value != "mad" {
+		t.Errorf("name %q, city %q", form.Elements["name"].Value, form.Elements["city"].Value)
+	}
+
+	// A multipart form can not parse an url-encoded request
+	if err := form.Bind(postValues("/profile", url.Values{"name": {"Jane"}})); err == nil {
+		t.Error("url-encoded request bound as multipart")
+	}
+}
@@ -0,0 +1,154 @@
+package goform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidCondition the operator of the condition is not supported
+var ErrInvalidCondition = errors.New("goform: invalid condition")
+
+// conditionOperators supported operators, in and notin take a list of values separated by commas
+var conditionOperators = map[string]bool{
+	"eq":       true,
+	"ne":       true,
+	"in":       true,
+	"notin":    true,
+	"empty":    true,
+	"notempty": true,
+}
+
+// Condition structure, the field is shown (Action "show") or enabled (Action "enable")
+// only if the submitted values of DependsOn match the Operator and Value.
+type Condition struct {
+	DependsOn string `json:"field"`
+	Operator  string `json:"operator"`
+	Value     string `json:"value"`
+	Action    string `json:"action"`
+}
+
+// ShowIf show the field only when the dependsOn field values match the condition,
+// operators: eq, ne, in, notin (comma separated values), empty and notempty.
+// Errors are registered in the form log.
+func (f *Form) ShowIf(fieldName string, dependsOn string, operator string, value string) {
+	f.addCondition(fieldName, Condition{dependsOn, operator, value, "show"})
+}
+
+// EnableIf enable the field only when the dependsOn field values match the condition,
+// see ShowIf for the operators. Errors are registered in the form log.
+func (f *Form) EnableIf(fieldName string, dependsOn string, operator string, value string) {
+	f.addCondition(fieldName, Condition{dependsOn, operator, value, "enable"})
+}
+
+// addCondition adds the condition to the field
+func (f *Form) addCondition(fieldName string, condition Condition) {
+
+	var err error
+
+	switch {
+	case !conditionOperators[condition.Operator]:
+		err = fmt.Errorf("%w: unknown operator %q", ErrInvalidCondition, condition.Operator)
+	case f.Elements[condition.DependsOn].Name == "":
+		err = fmt.Errorf("%w: field %s do not exists", ErrInvalidCondition, condition.DependsOn)
+	case f.Elements[fieldName].Name == "":
+		err = fmt.Errorf("%w: field %s do not exists", ErrInvalidCondition, fieldName)
+	}
+	if err != nil {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Invalid Condition", Err: err})
+		return
+	}
+
+	field := f.Elements[fieldName]
+	field.Conditions = append(field.Conditions, condition)
+	f.Elements[fieldName] = field
+}
+
+// Match returns true if the submitted values of the DependsOn field match the condition.
+func (condition Condition) Match(values url.Values) bool {
+
+	submitted := values[condition.DependsOn]
+
+	switch condition.Operator {
+	case "eq":
+		return contains(submitted, condition.Value)
+	case "ne":
+		return !contains(submitted, condition.Value)
+	case "in", "notin":
+		found := false
+		for _, value := range strings.Split(condition.Value, ",") {
+			found = found || contains(submitted, value)
+		}
+		return found == (condition.Operator == "in")
+	case "empty":
+		return strings.Join(submitted, "") == ""
+	case "notempty":
+		return strings.Join(submitted, "") != ""
+	}
+
+	return false
+}
+
+// contains returns true if value is one of the values
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns true if the field is shown and enabled for the submitted values,
+// the fields inside an inactive fieldset are inactive too. Inactive fields are
+// skipped by the validation and Decode.
+func (f *Form) Active(fieldName string, values url.Values) bool {
+
+	// Walk up the fieldsets of the field, depth limits invalid loops of sets
+	for depth := 0; fieldName != "" && depth <= len(f.Elements); depth++ {
+		field := f.Elements[fieldName]
+		for _, condition := range field.Conditions {
+			if !condition.Match(values) {
+				return false
+			}
+		}
+		fieldName = field.Set
+	}
+
+	return true
+}
+
+// HasConditions returns true if any field of the form has conditions.
+func (f *Form) HasConditions() bool {
+	for _, field := range f.Elements {
+		if len(field.Conditions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ConditionsAttr returns the data-goform-conditions attribute with the conditions of
+// the fields in JSON, used by the form templates and its script.
+func (f *Form) ConditionsAttr() template.HTMLAttr {
+
+	conditions := map[string][]Condition{}
+	for _, field := range f.Elements {
+		if len(field.Conditions) > 0 {
+			conditions[field.Name] = field.Conditions
+		}
+	}
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	text, err := json.Marshal(conditions)
+	if err != nil {
+		return ""
+	}
+
+	return renderAttributes(map[string]string{"data-goform-conditions": string(text)})
+}
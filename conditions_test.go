@@ -0,0 +1,74 @@
+package goform
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestConditionMatch(t *testing.T) {
+
+	values := url.Values{"account": {"business"}, "extras": {"gift", "fast"}, "note": {""}}
+
+	tests := []struct {
+		condition Condition
+		want      bool
+	}{
+		{Condition{"account", "eq", "business", "show"}, true},
+		{Condition{"account", "ne", "business", "show"}, false},
+		{Condition{"account", "in", "personal,business", "show"}, true},
+		{Condition{"account", "notin", "personal,business", "show"}, false},
+		{Condition{"extras", "eq", "fast", "show"}, true},
+		{Condition{"note", "empty", "", "show"}, true},
+		{Condition{"missing", "empty", "", "show"}, true},
+		{Condition{"account", "notempty", "", "show"}, true},
+	}
+
+	for _, test := range tests {
+		if got := test.condition.Match(values); got != test.want {
+			t.Errorf("%+v: got %v, want %v", test.condition, got, test.want)
+		}
+	}
+}
+
+func TestActiveInsideFieldset(t *testing.T) {
+
+	form := Create("signup", "POST", "/")
+	form.NewElement("radio", "account", "")
+	form.NewFieldset("company", "Company")
+	form.NewElement("text", "vat", "")
+	form.AddToSet("vat", "company")
+	form.ShowIf("company", "account", "eq", "business")
+
+	if form.Active("vat", url.Values{"account": {"personal"}}) {
+		t.Error("vat active inside a hidden fieldset")
+	}
+	if !form.Active("vat", url.Values{"account": {"business"}}) {
+		t.Error("vat inactive inside a shown fieldset")
+	}
+}
+
+func TestConditionsHTMLThemeGroups(t *testing.T) {
+
+	form := Create("signup", "POST", "/")
+	form.SetTemplateStyle("html")
+	form.NewElement("checkbox", "business", "yes")
+	form.NewElement("radio", "size", "")
+	form.SetOptions("size", []OptionItem{{Key: "s", Value: "Small"}, {Key: "l", Value: "Large"}})
+	form.ShowIf("size", "business", "notempty", "")
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The options and its texts are inside the group hidden by the script
+	group := strings.Index(html, `<span id="group_size">`)
+	end := strings.Index(html, "</span>")
+	if group < 0 || end < strings.Index(html, "Large") {
+		t.Errorf("radio options outside its group:\n%s", html)
+	}
+	if !strings.Contains(html, "data-goform-conditions") || !strings.Contains(html, "querySelectorAll") {
+		t.Errorf("render without the conditions script:\n%s", html)
+	}
+}
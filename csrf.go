@@ -0,0 +1,195 @@
+package goform
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the hidden field, header and cookie of the CSRF protection
+const (
+	csrfField  = "_goform_csrf"
+	csrfHeader = "X-CSRF-Token"
+	csrfCookie = "_goform_session"
+)
+
+// csrfExpiry default lifetime of the CSRF tokens
+const csrfExpiry = 12 * time.Hour
+
+// ErrInvalidCSRF the CSRF token is missing, altered, expired or from other session
+var ErrInvalidCSRF = errors.New("goform: invalid CSRF token")
+
+// ErrNoCSRFSession the form has CSRF enabled and is rendered without a session in the context,
+// the form is rendered without token and the error is logged
+var ErrNoCSRFSession = errors.New("goform: CSRF enabled without session, render with RenderRequest or RenderContext")
+
+// csrfSessionKey key of the CSRF session in the context
+type csrfSessionKey struct{}
+
+// csrfConfig structure, CSRF protection of the form
+type csrfConfig struct {
+	secret []byte
+	expiry time.Duration
+}
+
+// EnableCSRF add a hidden token field to the rendered form, signed with the secret and bound
+// to the session of the request. Render the form with RenderRequest(r) (e.g. in a template
+// {{ .Form.RenderRequest .Request }}) or RenderContext(r.Context(), w) and check the submitted
+// token with CSRFMiddleware or VerifyCSRF.
+func (f *Form) EnableCSRF(secret []byte) {
+	f.csrf.secret = secret
+}
+
+// SetCSRFExpiry set the lifetime of the CSRF tokens, 12 hours by default.
+func (f *Form) SetCSRFExpiry(expiry time.Duration) {
+	f.csrf.expiry = expiry
+}
+
+// VerifyCSRF returns ErrInvalidCSRF if the token submitted in the request is not valid for its session.
+func (f *Form) VerifyCSRF(r *http.Request) error {
+	return verifyCSRF(f.csrf.secret, r)
+}
+
+// CSRFMiddleware keeps a CSRF session cookie and rejects with 403 the requests with unsafe
+// methods (POST, PUT, PATCH, DELETE...) without a valid token, sent in the hidden field of
+// the forms or in the X-CSRF-Token header. The secret must be the one of EnableCSRF.
+func CSRFMiddleware(secret []byte) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			session := ""
+			if cookie, err := r.Cookie(csrfCookie); err == nil {
+				session = cookie.Value
+			}
+			if session == "" {
+				session = newCSRFSession()
+				http.SetCookie(w, &http.Cookie{
+					Name:     csrfCookie,
+					Value:    session,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			r = r.WithContext(WithCSRFSession(r.Context(), session))
+
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			default:
+				if err := verifyCSRF(secret, r); err != nil {
+					http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithCSRFSession returns a copy of ctx with the session of the CSRF tokens, use it to bind
+// the tokens to your own session instead of the cookie of CSRFMiddleware.
+func WithCSRFSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, csrfSessionKey{}, session)
+}
+
+// csrfSession returns the session of the context, empty if there is none
+func csrfSession(ctx context.Context) string {
+	session, _ := ctx.Value(csrfSessionKey{}).(string)
+	return session
+}
+
+// newCSRFSession returns a random session identifier
+func newCSRFSession() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// csrfToken returns the token of the session: its expiration time and the signature of both,
+// the session is not included, only signed.
+func (f *Form) csrfToken() (string, error) {
+
+	session := csrfSession(f.context())
+	if session == "" {
+		return "", ErrNoCSRFSession
+	}
+
+	expiry := f.csrf.expiry
+	if expiry <= 0 {
+		expiry = csrfExpiry
+	}
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+
+	return expires + "." + signature(f.csrf.secret, session+"|"+expires), nil
+}
+
+// renderCSRF write the hidden field with the token if the form has CSRF enabled
+func (f *Form) renderCSRF(w io.Writer) error {
+
+	if len(f.csrf.secret) == 0 {
+		return nil
+	}
+
+	// Without session the form is rendered without token, its submission is rejected
+	token, err := f.csrfToken()
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
+	tmpl, err := f.template("hidden")
+	if err != nil {
+		return err
+	}
+
+	field := EmptyField()
+	field.FieldType = "hidden"
+	field.Name = csrfField
+	field.ID = csrfField
+	field.Value = token
+
+	return tmpl.Execute(w, field)
+}
+
+// verifyCSRF check the token submitted in the header or the hidden field of the request
+func verifyCSRF(secret []byte, r *http.Request) error {
+
+	session := csrfSession(r.Context())
+	if session == "" {
+		if cookie, err := r.Cookie(csrfCookie); err == nil {
+			session = cookie.Value
+		}
+	}
+
+	token := r.Header.Get(csrfHeader)
+	if token == "" {
+		token = r.FormValue(csrfField)
+	}
+
+	expires, mac, ok := strings.Cut(token, ".")
+	if !ok || len(secret) == 0 || session == "" {
+		return ErrInvalidCSRF
+	}
+	if !hmac.Equal([]byte(mac), []byte(signature(secret, session+"|"+expires))) {
+		return ErrInvalidCSRF
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidCSRF
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package goform
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var csrfSecret = []byte("csrf secret")
+
+// csrfRequest returns a POST request with the token in the hidden field and the session cookie
+func csrfRequest(token string, session string) *http.Request {
+
+	r := postValues("/", url.Values{csrfField: {token}})
+	if session != "" {
+		r.AddCookie(&http.Cookie{Name: csrfCookie, Value: session})
+	}
+
+	return r
+}
+
+// csrfTokenAt returns a token of the session that expires at the time
+func csrfTokenAt(session string, expires time.Time) string {
+	unix := strconv.FormatInt(expires.Unix(), 10)
+	return unix + "." + signature(csrfSecret, session+"|"+unix)
+}
+
+func TestVerifyCSRF(t *testing.T) {
+
+	form := Create("contact", "POST", "/")
+	form.EnableCSRF(csrfSecret)
+
+	valid := csrfTokenAt("session-a", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		token   string
+		session string
+		valid   bool
+	}{
+		{"valid", valid, "session-a", true},
+		{"other session", valid, "session-b", false},
+		{"without session", valid, "", false},
+		{"expired", csrfTokenAt("session-a", time.Now().Add(-time.Minute)), "session-a", false},
+		{"extended expiry", strconv.FormatInt(time.Now().Add(48*time.Hour).Unix(), 10) + "." + strings.Split(valid, ".")[1], "session-a", false},
+		{"altered signature", valid + "x", "session-a", false},
+		{"empty", "", "session-a", false},
+		{"not a token", "token", "session-a", false},
+	}
+
+	for _, test := range tests {
+		err := form.VerifyCSRF(csrfRequest(test.token, test.session))
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, ErrInvalidCSRF) {
+			t.Errorf("%s: error %v, want ErrInvalidCSRF", test.name, err)
+		}
+	}
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+
+	form := Create("contact", "POST", "/")
+	form.EnableCSRF(csrfSecret)
+	form.NewElement("text", "message", "")
+
+	var page string
+	handler := CSRFMiddleware(csrfSecret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page = string(form.RenderRequest(r))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != csrfCookie || !cookies[0].HttpOnly {
+		t.Fatalf("session cookie %v", cookies)
+	}
+
+	match := regexp.MustCompile(`name="_goform_csrf"[^>]*value="([^"]+)"`).FindStringSubmatch(page)
+	if match == nil {
+		t.Fatalf("form without token:\n%s", page)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		session string
+		code    int
+	}{
+		{"valid", match[1], cookies[0].Value, http.StatusOK},
+		{"other session", match[1], "other", http.StatusForbidden},
+		{"without token", "", cookies[0].Value, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, csrfRequest(test.token, test.session))
+		if rec.Code != test.code {
+			t.Errorf("%s: status %d, want %d", test.name, rec.Code, test.code)
+		}
+	}
+}
+
+func TestCSRFRenderWithoutSession(t *testing.T) {
+
+	form := Create("contact", "POST", "/")
+	form.EnableCSRF(csrfSecret)
+	form.NewElement("text", "message", "")
+
+	html := string(form.Render())
+	if !strings.Contains(html, `name="message"`) {
+		t.Fatalf("form not rendered without session:\n%s", html)
+	}
+	if strings.Contains(html, csrfField) {
+		t.Errorf("token without session:\n%s", html)
+	}
+
+	buf := new(strings.Builder)
+	if err := form.RenderContext(WithCSRFSession(context.Background(), "session-a"), buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), csrfField) {
+		t.Errorf("form without token:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,32 @@
+package goform
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// OptionsHandler returns a handler that serves as JSON the options whose Key or Value
+// contains the "q" query parameter (case insensitive), at most limit options (0 for no limit).
+// Useful to fill the datalist of a text input with large option sets.
+func OptionsHandler(options []OptionItem, limit int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		query := strings.ToLower(r.URL.Query().Get("q"))
+		matches := []OptionItem{}
+
+		for _, option := range options {
+			if limit > 0 && len(matches) >= limit {
+				break
+			}
+			if strings.Contains(strings.ToLower(option.Key), query) || strings.Contains(strings.ToLower(option.Value), query) {
+				matches = append(matches, option)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(matches); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package goform
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptionsHandler(t *testing.T) {
+
+	cities := []OptionItem{
+		{Key: "AMS", Value: "Amsterdam"},
+		{Key: "BCN", Value: "Barcelona"},
+		{Key: "BER", Value: "Berlin"},
+		{Key: "KYO", Value: "Kyoto", Group: "Asia"},
+		{Key: "MAD", Value: "Madrid", Disabled: true},
+	}
+
+	tests := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{"", 0, []string{"AMS", "BCN", "BER", "KYO", "MAD"}},
+		{"", 2, []string{"AMS", "BCN"}},
+		{"b", 0, []string{"BCN", "BER"}},
+		{"B", 1, []string{"BCN"}},
+		{"ber", 0, []string{"BER"}},
+		{"LIN", 0, []string{"BER"}},
+		{"kyo", 0, []string{"KYO"}},
+		{"am", 0, []string{"AMS"}},
+		{"rid", 5, []string{"MAD"}},
+		{"lisbon", 0, []string{}},
+	}
+
+	for _, tt := range tests {
+
+		w := httptest.NewRecorder()
+		OptionsHandler(cities, tt.limit).ServeHTTP(w, httptest.NewRequest("GET", "/cities?q="+tt.query, nil))
+
+		if got := w.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("q=%s: content type %q", tt.query, got)
+		}
+
+		var options []OptionItem
+		if err := json.Unmarshal(w.Body.Bytes(), &options); err != nil {
+			t.Fatalf("q=%s: %v in %s", tt.query, err, w.Body)
+		}
+
+		keys := []string{}
+		for _, option := range options {
+			keys = append(keys, option.Key)
+		}
+		if !reflect.DeepEqual(keys, tt.want) {
+			t.Errorf("q=%s, limit %d: options %v, want %v", tt.query, tt.limit, keys, tt.want)
+		}
+	}
+
+	// The options keep its group and disabled state
+	w := httptest.NewRecorder()
+	OptionsHandler(cities, 0).ServeHTTP(w, httptest.NewRequest("GET", "/cities?q=k", nil))
+	if body := w.Body.String(); !strings.Contains(body, `{"key":"KYO","value":"Kyoto","group":"Asia"}`) {
+		t.Errorf("json %s", body)
+	}
+}
+
+func TestRenderDatalist(t *testing.T) {
+
+	form := Create("trip", "POST", "/trip")
+	form.SetTemplateStyle("html")
+	form.NewElement("text", "city", "")
+	form.SetOptions("city", []OptionItem{{Key: "AMS", Value: "Amsterdam"}, {Key: "MAD", Value: "Madrid", Disabled: true}})
+	form.NewElement("text", "name", "")
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{`list="city_list"`, `<datalist id="city_list">`, `<option value="AMS">Amsterdam</option>`, `<option value="MAD" disabled>Madrid</option>`} {
+		if !strings.Contains(html, text) {
+			t.Errorf("render without %s:\n%s", text, html)
+		}
+	}
+	if strings.Contains(html, "name_list") {
+		t.Errorf("datalist of an input without options:\n%s", html)
+	}
+}
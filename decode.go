@@ -0,0 +1,221 @@
+package goform
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// timeLayouts formats accepted for time.Time fields (date, datetime-local, month and time inputs)
+var timeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01",
+	"15:04",
+	"15:04:05",
+}
+
+// Decode parse the submitted request and set its values into the struct pointed by dst.
+// Struct fields are matched with the form elements by its name (the "name" key of the
+// form tag or the field name), normalized like NewElement does, fields without a form
+// element or inactive (see ShowIf) are ignored. Unchecked checkboxes set bool fields to false.
+func (f *Form) Decode(r *http.Request, dst any) error {
+
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("goform: Decode of non-struct pointer %T", dst)
+	}
+
+	if err := f.parseRequest(r); err != nil {
+		return err
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if r.MultipartForm != nil {
+		files = r.MultipartForm.File
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("form")
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		options, err := parseFormTag(tag)
+		if err != nil {
+			return fmt.Errorf("goform: field %s: %w", sf.Name, err)
+		}
+
+		fieldName := options["name"]
+		if fieldName == "" {
+			fieldName = sf.Name
+		}
+		fieldName = normalizeName(fieldName)
+
+		element, ok := f.Elements[fieldName]
+		if !ok || !f.Active(fieldName, r.Form) {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		switch {
+		case element.FieldType == "file":
+			err = decodeFiles(fv, files[fieldName])
+		case element.FieldType == "signedhidden":
+			var value string
+			if value, err = f.openValue(fieldName, r.Form.Get(fieldName)); err == nil {
+				err = decodeValue(fv, value)
+			}
+		case element.FieldType == "checkbox" && fv.Kind() == reflect.Bool:
+			fv.SetBool(r.Form.Has(fieldName))
+		default:
+			values, submitted := r.Form[fieldName]
+			if !submitted {
+				// Nothing selected in a multiselect or checkboxgroup
+				if multiTypes[element.FieldType] && fv.Kind() == reflect.Slice {
+					fv.Set(reflect.Zero(fv.Type()))
+				}
+				continue
+			}
+			err = decodeValues(fv, values)
+		}
+
+		if err != nil {
+			return fmt.Errorf("goform: field %s: %w", sf.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// decodeFiles set the uploaded files into a *multipart.FileHeader or a slice of them
+func decodeFiles(v reflect.Value, files []*multipart.FileHeader) error {
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	switch {
+	case v.Type() == fileHeaderType:
+		v.Set(reflect.ValueOf(files[0]))
+	case v.Kind() == reflect.Slice && v.Type().Elem() == fileHeaderType:
+		v.Set(reflect.ValueOf(files))
+	default:
+		return fmt.Errorf("unsupported type %s for file input", v.Type())
+	}
+
+	return nil
+}
+
+// decodeValues set the submitted values, slices (except []byte) receive all the values of the key
+func decodeValues(v reflect.Value, values []string) error {
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := decodeValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	return decodeValue(v, values[0])
+}
+
+// decodeValue convert the text value into the type of v
+func decodeValue(v reflect.Value, value string) error {
+
+	if v.Kind() == reflect.Pointer {
+		if value == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		if value == "" {
+			v.Set(reflect.Zero(timeType))
+			return nil
+		}
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid time %q", value)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.SetBytes([]byte(value))
+	case reflect.Bool:
+		if value == "" || value == "on" {
+			v.SetBool(value == "on")
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			v.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			v.SetFloat(0)
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
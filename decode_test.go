@@ -0,0 +1,220 @@
+package goform
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeTarget struct with a field of each supported type
+type decodeTarget struct {
+	Name    string
+	Age     int
+	Small   int8
+	Count   uint
+	Price   float64
+	Ratio   float32
+	News    bool
+	Admin   bool `form:"name=is admin"`
+	Born    time.Time
+	Meeting time.Time
+	Tags    []string
+	Scores  []int
+	Raw     []byte
+	Nick    *string
+	Skip    string `form:"-"`
+	Missing string
+}
+
+// decodeForm returns a form with an element for each field of decodeTarget except Missing
+func decodeForm() *Form {
+
+	form := Create("profile", "POST", "/profile")
+	for _, name := range []string{"name", "age", "small", "count", "price", "ratio", "born", "meeting", "tags", "scores", "raw", "nick", "skip"} {
+		form.NewElement("text", name, "")
+	}
+	form.NewElement("checkbox", "news", "")
+	form.NewElement("checkbox", "isadmin", "")
+
+	return form
+}
+
+func TestDecode(t *testing.T) {
+
+	values := url.Values{
+		"name":    {"Jane"},
+		"age":     {"42"},
+		"small":   {"-8"},
+		"count":   {"7"},
+		"price":   {"9.95"},
+		"ratio":   {"0.5"},
+		"news":    {"on"},
+		"born":    {"1990-05-17"},
+		"meeting": {"2024-03-09T14:30"},
+		"tags":    {"go", "html"},
+		"scores":  {"1", "2", "3"},
+		"raw":     {"hello"},
+		"nick":    {"jd"},
+		"skip":    {"ignored"},
+		"missing": {"ignored"},
+	}
+
+	dst := decodeTarget{Admin: true, Skip: "kept", Missing: "kept"}
+	if err := decodeForm().Decode(postValues("/profile", values), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	nick := "jd"
+	want := decodeTarget{
+		Name:    "Jane",
+		Age:     42,
+		Small:   -8,
+		Count:   7,
+		Price:   9.95,
+		Ratio:   0.5,
+		News:    true,
+		Admin:   false,
+		Born:    time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Meeting: time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC),
+		Tags:    []string{"go", "html"},
+		Scores:  []int{1, 2, 3},
+		Raw:     []byte("hello"),
+		Nick:    &nick,
+		Skip:    "kept",
+		Missing: "kept",
+	}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("decoded\n%+v\nwant\n%+v", dst, want)
+	}
+}
+
+func TestDecodeEmptyValues(t *testing.T) {
+
+	nick := "jd"
+	dst := decodeTarget{Name: "Jane", Age: 42, Price: 1, Born: time.Now(), Nick: &nick, Raw: []byte("x")}
+	values := url.Values{"name": {""}, "age": {""}, "price": {""}, "born": {""}, "nick": {""}, "raw": {""}}
+
+	if err := decodeForm().Decode(postValues("/profile", values), &dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Name != "" || dst.Age != 0 || dst.Price != 0 || !dst.Born.IsZero() || dst.Nick != nil || len(dst.Raw) != 0 {
+		t.Errorf("decoded %+v, want zero values", dst)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		values url.Values
+	}{
+		{"int", url.Values{"age": {"forty"}}},
+		{"int overflow", url.Values{"small": {"300"}}},
+		{"negative uint", url.Values{"count": {"-1"}}},
+		{"float", url.Values{"price": {"cheap"}}},
+		{"time", url.Values{"born": {"17/05/1990"}}},
+		{"slice item", url.Values{"scores": {"1", "two"}}},
+	}
+
+	for _, tt := range tests {
+		var dst decodeTarget
+		err := decodeForm().Decode(postValues("/profile", tt.values), &dst)
+		if err == nil {
+			t.Errorf("%s: decoded %+v, want error", tt.name, dst)
+		}
+	}
+
+	var numErr *strconv.NumError
+	var dst decodeTarget
+	if err := decodeForm().Decode(postValues("/profile", url.Values{"age": {"x"}}), &dst); !errors.As(err, &numErr) {
+		t.Errorf("error %v, want *strconv.NumError", err)
+	}
+
+	for _, target := range []any{dst, nil, new(int)} {
+		if err := decodeForm().Decode(postValues("/profile", url.Values{}), target); err == nil {
+			t.Errorf("Decode into %T without error", target)
+		}
+	}
+}
+
+func TestDecodeFiles(t *testing.T) {
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", "Jane")
+	for _, name := range []string{"a.png", "b.png"} {
+		part, _ := writer.CreateFormFile("gallery", name)
+		part.Write([]byte("image " + name))
+	}
+	part, _ := writer.CreateFormFile("avatar", "me.png")
+	part.Write([]byte("image"))
+	writer.Close()
+	data := body.Bytes()
+
+	r := httptest.NewRequest(http.MethodPost, "/profile", bytes.NewReader(data))
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+
+	form := Create("profile", "POST", "/profile")
+	form.SetMultipartFormData("enabled")
+	form.NewElement("text", "name", "")
+	form.NewElement("file", "avatar", "")
+	form.NewElement("file", "gallery", "")
+	form.NewElement("file", "resume", "")
+
+	var dst struct {
+		Name    string
+		Avatar  *multipart.FileHeader
+		Gallery []*multipart.FileHeader
+		Resume  *multipart.FileHeader
+	}
+	if err := form.Decode(r, &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Name != "Jane" || dst.Avatar == nil || dst.Avatar.Filename != "me.png" || dst.Resume != nil {
+		t.Errorf("decoded %+v", dst)
+	}
+	if len(dst.Gallery) != 2 || dst.Gallery[0].Filename != "a.png" || dst.Gallery[1].Filename != "b.png" {
+		t.Errorf("gallery %+v", dst.Gallery)
+	}
+
+	var wrong struct{ Avatar string }
+	r = httptest.NewRequest(http.MethodPost, "/profile", bytes.NewReader(data))
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := form.Decode(r, &wrong); err == nil || !strings.Contains(err.Error(), "unsupported type") {
+		t.Errorf("file decoded into a string field, error %v", err)
+	}
+}
+
+func TestDecodeFromStruct(t *testing.T) {
+
+	type profile struct {
+		Name string
+		Raw  []byte
+		News bool
+	}
+
+	form, err := FromStruct("profile", "POST", "/profile", profile{Raw: []byte("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := form.Elements["raw"].Value; got != "hello" {
+		t.Errorf("raw: value %q, want %q", got, "hello")
+	}
+
+	var dst profile
+	if err := form.Decode(postValues("/profile", url.Values{"name": {"Jane"}, "raw": {"hello"}, "news": {"on"}}), &dst); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dst, profile{"Jane", []byte("hello"), true}) {
+		t.Errorf("decoded %+v", dst)
+	}
+}
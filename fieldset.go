@@ -0,0 +1,57 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSet the set do not exists, is not a fieldset or would contain itself
+var ErrInvalidSet = errors.New("goform: invalid set")
+
+// NewFieldset insert a new fieldset, the elements added to it with AddToSet are
+// rendered inside the fieldset under the legend text. Errors are registered in the form log.
+func (f *Form) NewFieldset(setName string, legend string) string {
+
+	setName, err := f.AddElement("fieldset", setName, "")
+	if err != nil {
+		f.logElementError("fieldset", setName, err)
+		return setName
+	}
+	f.SetLabel(setName, legend)
+
+	return setName
+}
+
+// AddToSet move the field inside the fieldset set, fieldsets can be nested.
+// Errors are registered in the form log.
+func (f *Form) AddToSet(fieldName string, set string) {
+
+	if err := f.checkSet(fieldName, set); err != nil {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Invalid Set", Err: err})
+		return
+	}
+
+	field := f.Elements[fieldName]
+	field.Set = set
+	f.Elements[fieldName] = field
+}
+
+// checkSet returns an error if the field can not be added to the set
+func (f *Form) checkSet(fieldName string, set string) error {
+
+	if _, ok := f.Elements[fieldName]; !ok {
+		return fmt.Errorf("%w: field %s do not exists", ErrInvalidSet, fieldName)
+	}
+
+	// Walk up the parents of the set, the field can not be one of them
+	for parent := set; parent != ""; parent = f.Elements[parent].Set {
+		if f.Elements[parent].FieldType != "fieldset" {
+			return fmt.Errorf("%w: %s is not a fieldset", ErrInvalidSet, parent)
+		}
+		if parent == fieldName {
+			return fmt.Errorf("%w: %s can not contain itself", ErrInvalidSet, fieldName)
+		}
+	}
+
+	return nil
+}
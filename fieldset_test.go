@@ -0,0 +1,47 @@
+package goform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewFieldsetDuplicate(t *testing.T) {
+
+	form := Create("address", "POST", "/")
+	form.NewElement("text", "address", "")
+	form.SetLabel("address", "Street")
+
+	form.NewFieldset("address", "Address block")
+
+	if got := form.Elements["address"]; got.FieldType != "text" || got.Label != "Street" {
+		t.Errorf("existing element changed: type %q, label %q", got.FieldType, got.Label)
+	}
+	if errs := form.Errors(); len(errs) != 1 || !errors.Is(errs[0].Err, ErrDuplicateField) {
+		t.Errorf("errors %v, want ErrDuplicateField", errs)
+	}
+}
+
+func TestFieldsetRender(t *testing.T) {
+
+	form := Create("address", "POST", "/")
+	form.NewFieldset("address", "Address block")
+	form.NewElement("text", "street", "")
+	form.AddToSet("street", "address")
+	form.AddToSet("address", "address")
+
+	if errs := form.Errors(); len(errs) != 1 || !errors.Is(errs[0].Err, ErrInvalidSet) {
+		t.Errorf("errors %v, want ErrInvalidSet", errs)
+	}
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legend := strings.Index(html, "Address block")
+	street := strings.Index(html, `name="street"`)
+	end := strings.Index(html, "</fieldset>")
+	if legend < 0 || street < legend || end < street {
+		t.Errorf("street is not inside the fieldset:\n%s", html)
+	}
+}
@@ -3,16 +3,27 @@ package goform
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
 	"log"
-	"os"
-	"path"
+	"net/http"
 	"sort"
 	"strings"
 )
 
 var (
-	logForm    []ErrorItem
+	// ErrDuplicateField the form already have a field with the same name
+	ErrDuplicateField = errors.New("goform: field already exists")
+	// ErrUnknownType the field type is not supported
+	ErrUnknownType = errors.New("goform: type do not exists")
+	// ErrFieldNotFound the form do not have a field with the name
+	ErrFieldNotFound = errors.New("goform: field do not exists")
+)
+
+var (
 	fieldTypes = map[string]string{
 		"label":     "label",
 		"text":      "text",
@@ -27,6 +38,27 @@ var (
 		"button":    "button",
 		"submit":    "submit",
 		"row":       "row",
+		// Multi-valued inputs
+		"multiselect":   "multiselect",
+		"checkboxgroup": "checkboxgroup",
+		// Group of elements
+		"fieldset": "fieldset",
+		"repeater": "repeater",
+		// Hidden input with signed (or encrypted) value, see SetSigningKey
+		"signedhidden": "hidden",
+		// HTML5 inputs
+		"email":          "email",
+		"number":         "number",
+		"tel":            "tel",
+		"url":            "url",
+		"search":         "search",
+		"date":           "date",
+		"time":           "time",
+		"datetime-local": "datetime-local",
+		"month":          "month",
+		"week":           "week",
+		"color":          "color",
+		"range":          "range",
 	}
 )
 
@@ -43,9 +75,20 @@ type Form struct {
 	Elements          map[string]Field
 	Classes           []string
 	CSS               map[string]string
-	FormText          string
-	FormTemplates     map[string]*template.Template
-	GroupClass        []string
+	// Deprecated: FormText is not used, rendering does not modify the form.
+	FormText string
+	// Deprecated: FormTemplates is not used, rendering does not modify the form.
+	FormTemplates map[string]*template.Template
+	GroupClass    []string
+	errors        []ErrorItem
+	themeFS       *fsTheme
+	ctx           context.Context
+	Steps         []Step
+	wizard        wizardConfig
+	progress      *StepProgress
+	csrf          csrfConfig
+	antiBot       antiBotConfig
+	signed        signedConfig
 }
 
 // Element structure.
@@ -71,12 +114,77 @@ type Field struct {
 	Params      map[string]string
 	Set         string
 	GroupClass  []string
+	Rules       []Rule
+	Checked     bool
+	Min         string
+	Max         string
+	Step        string
+	Pattern     string
+	Values      []string
+	// OptionProvider resolves the Options at render time, see SetOptionProvider
+	OptionProvider OptionProvider
+	Disabled       bool
+	// Content rendered elements of a fieldset, filled at render time
+	Content template.HTML
+	// Repeater configuration of a repeater, see NewRepeater
+	Repeater *Repeater
+	// Rows values of the rows of a repeater
+	Rows []map[string]string
+	// RowsHTML and RowTemplate rendered rows of a repeater, filled at render time
+	RowsHTML    []template.HTML
+	RowTemplate template.HTML
+	// Conditions show/enable the field depending on other fields, see ShowIf and EnableIf
+	Conditions []Condition
+	// WizardStep name of the step of a wizard form, see NewStep
+	WizardStep string
+	// RichLabel and RichHelpText HTML content of the label and help-text, sanitized at render
+	RichLabel    template.HTML
+	RichHelpText template.HTML
+}
+
+// HasValue returns true if the key is one of the selected values of the field,
+// used by the templates of multiselect and checkboxgroup.
+func (field Field) HasValue(key string) bool {
+	for _, value := range field.Values {
+		if value == key {
+			return true
+		}
+	}
+	return false
 }
 
-// OptionItem structure.
+// OptionGroups returns the options of the field split by its Group,
+// consecutive options with the same Group make a single group.
+func (field Field) OptionGroups() []OptionGroup {
+
+	groups := []OptionGroup{}
+
+	for _, option := range field.Options {
+		last := len(groups) - 1
+		if last >= 0 && groups[last].Label == option.Group {
+			groups[last].Options = append(groups[last].Options, option)
+		} else {
+			groups = append(groups, OptionGroup{option.Group, []OptionItem{option}})
+		}
+	}
+
+	return groups
+}
+
+// OptionItem structure, options with the same Group are rendered together
+// (e.g. inside an <optgroup>), Attributes are added to the option (e.g. data-code - ES).
 type OptionItem struct {
-	Key   string
-	Value string
+	Key        string            `json:"key"`
+	Value      string            `json:"value"`
+	Group      string            `json:"group,omitempty"`
+	Disabled   bool              `json:"disabled,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// OptionGroup structure, consecutive options of the same group.
+type OptionGroup struct {
+	Label   string
+	Options []OptionItem
 }
 
 type FieldIndex struct {
@@ -84,9 +192,11 @@ type FieldIndex struct {
 	Field Field
 }
 
+// ErrorItem structure, Err holds the sentinel error when there is one.
 type ErrorItem struct {
 	RelatedTo string
 	Message   string
+	Err       error
 }
 
 //=============================================================================
@@ -109,6 +219,15 @@ func Create(name string, method string, action string) *Form {
 		"",
 		make(map[string]*template.Template),
 		[]string{},
+		[]ErrorItem{},
+		nil,
+		nil,
+		[]Step{},
+		wizardConfig{},
+		nil,
+		csrfConfig{},
+		antiBotConfig{},
+		signedConfig{},
 	}
 }
 
@@ -119,17 +238,22 @@ func (f *Form) SetMultipartFormData(status string) {
 }
 
 // SetTemplateStyle set style format, (html or bootstrap5: default option)
+// or a theme registered with RegisterThemeFS
 func (f *Form) SetTemplateStyle(style string) {
 
 	f.TemplateStyle = style
+	f.TemplateSource = ""
+	f.themeFS = nil
 }
 
 // SetOwnTemplateStyle set style format, target different templates folder
-// In case if any one need to use a custom templates
+// In case if any one need to use a custom templates, the templates are read from
+// templates/<style>.html and templates/<style>/<type>.html in the working directory
 func (f *Form) SetOwnTemplateStyle(style string) {
 
 	f.TemplateStyle = style
 	f.TemplateSource = "OWN"
+	f.themeFS = ownTheme(style)
 }
 
 // DefaultGroupClass set default group classes for all the elements
@@ -138,76 +262,192 @@ func (f *Form) DefaultGroupClass(width string) {
 	f.GroupClass = append(f.GroupClass, width)
 }
 
-// Render returns form generated in plain format
+// Render returns form generated in plain format, errors are logged.
 func (f *Form) Render() template.HTML {
 
-	var tmpl *template.Template
+	text, err := f.RenderString()
+	if err != nil {
+		log.Println(err)
+	}
+
+	return template.HTML(text)
+}
+
+// RenderRequest returns form generated in plain format with the context of the request,
+// needed by EnableCSRF and the option providers that use the request, errors are logged.
+func (f *Form) RenderRequest(r *http.Request) template.HTML {
+
+	buf := new(bytes.Buffer)
+	if err := f.RenderContext(r.Context(), buf); err != nil {
+		log.Println(err)
+		return ""
+	}
+
+	return template.HTML(buf.String())
+}
+
+// RenderString returns form generated in plain format or the template error.
+func (f *Form) RenderString() (string, error) {
+
 	buf := new(bytes.Buffer)
-	cwd := ""
-	var err error
+	if err := f.RenderTo(buf); err != nil {
+		return "", err
+	}
 
-	if f.TemplateSource == "OWN" {
-		cwd, _ = os.Getwd()
-		cwd += path.Join("/templates/", f.TemplateStyle, ".html")
+	return buf.String(), nil
+}
 
-		tmpl, err = template.ParseFiles(cwd)
-		if err != nil {
-			panic(err)
-		}
+// RenderTo write the form generated to w, returns the parse or execution errors of the templates.
+func (f *Form) RenderTo(w io.Writer) error {
+	return f.RenderContext(context.Background(), w)
+}
+
+// RenderContext write the form generated to w, ctx is passed to the option providers of the fields.
+func (f *Form) RenderContext(ctx context.Context, w io.Writer) error {
 
-	} else {
-		tmpl = HTMLTemplate(f.TemplateStyle, "form")
+	tmpl, err := f.template("form")
+	if err != nil {
+		return err
 	}
 
-	tmpl.Execute(buf, f)
+	// The form template renders the elements, a copy carries the context
+	form := *f
+	form.ctx = ctx
 
-	return template.HTML(buf.String())
+	return tmpl.Execute(w, &form)
+}
+
+// context returns the context of the render, background if there is none
+func (f *Form) context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
 }
 
-// RenderElements returns form elements generated in plain format
+// RenderElements returns form elements generated in plain format, errors are logged.
 func (f *Form) RenderElements() template.HTML {
 
-	elementsSort := f.SortElements()
+	elements, err := f.ElementsHTML()
+	if err != nil {
+		log.Println(err)
+	}
+
+	return elements
+}
+
+// ElementsHTML returns form elements generated in plain format or the template error,
+// used by the form templates to render the elements.
+func (f *Form) ElementsHTML() (template.HTML, error) {
 
-	var tmpl *template.Template
 	buf := new(bytes.Buffer)
-	cwd := ""
-	var err error
+	if err := f.RenderElementsTo(buf); err != nil {
+		return "", err
+	}
+
+	return template.HTML(buf.String()), nil
+}
+
+// RenderElementsTo write the form elements generated to w, returns the parse or execution errors of the templates.
+// The form is not modified, so the same form can be rendered several times and from several goroutines.
+func (f *Form) RenderElementsTo(w io.Writer) error {
+
+	elementsSort := f.SortElements()
+	formTemplates := make(map[string]*template.Template, len(f.FormTypes))
 
 	// Load ONCE all the necesary templates depending the input types
 	for keyTemplate := range f.FormTypes {
 
-		if f.TemplateSource == "OWN" {
-			cwd, _ = os.Getwd()
-			cwd += path.Join("templates", f.TemplateStyle, keyTemplate, ".html")
+		tmpl, err := f.template(keyTemplate)
+		if err != nil {
+			return err
+		}
+
+		formTemplates[keyTemplate] = tmpl
+	}
+
+	// And the templates of the elements of the repeaters
+	for _, itemForm := range elementsSort {
+		if itemForm.Repeater == nil || itemForm.Repeater.Group == nil {
+			continue
+		}
+		for keyTemplate := range itemForm.Repeater.Group.FormTypes {
+			if _, ok := formTemplates[keyTemplate]; ok {
+				continue
+			}
 
-			tmpl, err = template.ParseFiles(cwd)
+			tmpl, err := f.template(keyTemplate)
 			if err != nil {
-				panic(err)
+				return err
 			}
-		} else {
-			tmpl = HTMLTemplate(f.TemplateStyle, keyTemplate)
+
+			formTemplates[keyTemplate] = tmpl
 		}
+	}
 
-		f.FormTemplates[keyTemplate] = tmpl
+	// Group the elements by its fieldset, elements without a valid set are in the main group
+	sets := map[string][]Field{}
+	for _, itemForm := range elementsSort {
+		set := itemForm.Set
+		if f.Elements[set].FieldType != "fieldset" {
+			set = ""
+		}
+		sets[set] = append(sets[set], itemForm)
 	}
 
+	if err := f.renderFields(w, formTemplates, sets, sets[""]); err != nil {
+		return err
+	}
+
+	if err := f.renderCSRF(w); err != nil {
+		return err
+	}
+
+	return f.renderAntiBot(w)
+}
+
+// renderFields apply the template to each field, the fieldsets render its own elements first
+func (f *Form) renderFields(w io.Writer, formTemplates map[string]*template.Template, sets map[string][]Field, fields []Field) error {
+
 	// Apply the template to each item of the form
-	for _, itemForm := range elementsSort {
+	for _, itemForm := range fields {
 
 		// Apply the default classes if exists, only if the element have not own group classes
 		if len(itemForm.GroupClass) == 0 && len(f.GroupClass) > 0 {
 			itemForm.GroupClass = f.GroupClass
 		}
 
-		f.FormTemplates[itemForm.FieldType].Execute(buf, itemForm)
-		f.FormText += buf.String()
+		itemForm, err := resolveOptions(f.context(), itemForm)
+		if err != nil {
+			return err
+		}
+
+		if itemForm.FieldType == "fieldset" {
+			buf := new(bytes.Buffer)
+			if err := f.renderFields(buf, formTemplates, sets, sets[itemForm.Name]); err != nil {
+				return err
+			}
+			itemForm.Content = template.HTML(buf.String())
+		}
+
+		if itemForm.FieldType == "signedhidden" {
+			if itemForm.Value, err = f.sealValue(itemForm.Name, itemForm.Value); err != nil {
+				return err
+			}
+		}
+
+		if itemForm.FieldType == "repeater" {
+			if itemForm, err = f.renderRepeater(formTemplates, itemForm); err != nil {
+				return err
+			}
+		}
 
-		// Clear buffer
-		buf = new(bytes.Buffer)
+		if err := formTemplates[itemForm.FieldType].Execute(w, itemForm); err != nil {
+			return err
+		}
 	}
 
-	return template.HTML(f.FormText)
+	return nil
 }
 
 // EmptyField create and return empty form field
@@ -228,11 +468,22 @@ func EmptyField() Field {
 	field.HelpText = ""
 	field.Params = map[string]string{}
 	field.GroupClass = []string{}
+	field.Rules = []Rule{}
+	field.Min = ""
+	field.Max = ""
+	field.Step = ""
+	field.Pattern = ""
+	field.Values = []string{}
+	field.Disabled = false
+	field.Conditions = []Condition{}
+	field.WizardStep = ""
+	field.RichLabel = ""
+	field.RichHelpText = ""
 
 	return field
 }
 
-// SortElements create and return empty form field
+// SortElements returns the form elements sorted by its position
 func (f *Form) SortElements() []Field {
 
 	elementsIdexed := []FieldIndex{}
@@ -243,9 +494,12 @@ func (f *Form) SortElements() []Field {
 		elementsIdexed = append(elementsIdexed, FieldIndex{v.Position, v})
 	}
 
-	// Slice sort
+	// Slice sort, elements with the same position are sorted by name
 	sort.Slice(elementsIdexed, func(i, j int) bool {
-		return elementsIdexed[i].Index < elementsIdexed[j].Index
+		if elementsIdexed[i].Index != elementsIdexed[j].Index {
+			return elementsIdexed[i].Index < elementsIdexed[j].Index
+		}
+		return elementsIdexed[i].Field.Name < elementsIdexed[j].Field.Name
 	})
 
 	// Remove the index
@@ -256,42 +510,178 @@ func (f *Form) SortElements() []Field {
 	return formElementsSorted
 }
 
-// NewElement insert new form element
+// NewElement insert new form element, errors are registered in the form log.
 func (f *Form) NewElement(fieldType string, fieldName string, fieldValue string) string {
 
-	// fieldName remove spaces and convert un lowercase
-	fieldName = strings.ToLower(fieldName)
-	fieldName = strings.Replace(fieldName, " ", "", -1)
+	fieldName, err := f.AddElement(fieldType, fieldName, fieldValue)
+	f.logElementError(fieldType, fieldName, err)
 
-	_, typeOk := fieldTypes[fieldType]
-	// If the key exists
-	if typeOk {
+	return fieldName
+}
 
-		field := EmptyField()
-		field.Position = len(f.Elements) + 1
-		field.FieldType = fieldType
-		field.Name = fieldName
-		field.ID = fieldName
-		field.Value = fieldValue
+// logElementError register in the form log the error of AddElement
+func (f *Form) logElementError(fieldType string, fieldName string, err error) {
+	switch {
+	case errors.Is(err, ErrDuplicateField):
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Field Already Exists", Err: ErrDuplicateField})
+	case errors.Is(err, ErrUnknownType):
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldType, Message: "Type Do Not Exists", Err: ErrUnknownType})
+	}
+}
 
-		// Apped/Or Increase the input-type counter of FormTypes map
-		// This map will is used in the RenderElements function
-		f.FormTypes[fieldType]++
+// AddElement insert new form element, returns the normalized field name
+// and ErrDuplicateField or ErrUnknownType if the element can not be inserted.
+func (f *Form) AddElement(fieldType string, fieldName string, fieldValue string) (string, error) {
 
-		_, fieldOk := f.Elements[fieldName]
-		if !fieldOk {
-			f.Elements[fieldName] = field
-		} else {
-			logForm = append(logForm, ErrorItem{RelatedTo: fieldName, Message: "Field Already Exists"})
+	fieldName = normalizeName(fieldName)
+
+	// If the key do not exists
+	if _, typeOk := fieldTypes[fieldType]; !typeOk {
+		return fieldName, fmt.Errorf("%w: %s", ErrUnknownType, fieldType)
+	}
+
+	if _, fieldOk := f.Elements[fieldName]; fieldOk {
+		return fieldName, fmt.Errorf("%w: %s", ErrDuplicateField, fieldName)
+	}
+
+	field := EmptyField()
+	field.Position = f.nextPosition()
+	field.FieldType = fieldType
+	field.Name = fieldName
+	field.ID = fieldName
+	field.Value = fieldValue
+	field.WizardStep = f.wizard.current
+
+	// Apped/Or Increase the input-type counter of FormTypes map
+	// This map will is used in the RenderElements function
+	f.FormTypes[fieldType]++
+
+	f.Elements[fieldName] = field
+
+	return fieldName, nil
+}
+
+// normalizeName remove spaces and convert un lowercase
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	return strings.Replace(name, " ", "", -1)
+}
+
+// nextPosition returns the position after the last element
+func (f *Form) nextPosition() int {
+	last := 0
+	for _, field := range f.Elements {
+		if field.Position > last {
+			last = field.Position
 		}
+	}
+	return last + 1
+}
+
+// RemoveElement remove the field from the form, the elements of a removed
+// fieldset are moved to its parent set. Errors are registered in the form log.
+func (f *Form) RemoveElement(fieldName string) {
+
+	field, ok := f.Elements[fieldName]
+	if !ok {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Field Do Not Exists", Err: ErrFieldNotFound})
+		return
+	}
+
+	for name, element := range f.Elements {
+		if element.Set == fieldName {
+			element.Set = field.Set
+			f.Elements[name] = element
+		}
+	}
+
+	delete(f.Elements, fieldName)
+
+	f.FormTypes[field.FieldType]--
+	if f.FormTypes[field.FieldType] <= 0 {
+		delete(f.FormTypes, field.FieldType)
+	}
+
+	f.Renumber()
+}
+
+// InsertBefore insert new form element just before the target field.
+func (f *Form) InsertBefore(target string, fieldType string, fieldName string, fieldValue string) string {
+	return f.insertAt(target, 0, fieldType, fieldName, fieldValue)
+}
+
+// InsertAfter insert new form element just after the target field.
+func (f *Form) InsertAfter(target string, fieldType string, fieldName string, fieldValue string) string {
+	return f.insertAt(target, 1, fieldType, fieldName, fieldValue)
+}
+
+// insertAt insert new form element at the position of the target plus offset
+func (f *Form) insertAt(target string, offset int, fieldType string, fieldName string, fieldValue string) string {
 
-	} else {
-		logForm = append(logForm, ErrorItem{RelatedTo: fieldType, Message: "Type Do Not Exists"})
+	if _, ok := f.Elements[target]; !ok {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: target, Message: "Field Do Not Exists", Err: ErrFieldNotFound})
+		return normalizeName(fieldName)
 	}
 
+	fieldName, err := f.AddElement(fieldType, fieldName, fieldValue)
+	if err != nil {
+		f.logElementError(fieldType, fieldName, err)
+		return fieldName
+	}
+
+	f.Renumber()
+	f.MoveTo(fieldName, f.Elements[target].Position+offset)
+
 	return fieldName
 }
 
+// MoveTo move the field to the position (starting at 1), the elements after it are shifted.
+func (f *Form) MoveTo(fieldName string, position int) {
+
+	if _, ok := f.Elements[fieldName]; !ok {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Field Do Not Exists", Err: ErrFieldNotFound})
+		return
+	}
+
+	order := []string{}
+	for _, field := range f.SortElements() {
+		if field.Name != fieldName {
+			order = append(order, field.Name)
+		}
+	}
+
+	index := position - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(order) {
+		index = len(order)
+	}
+
+	order = append(order[:index], append([]string{fieldName}, order[index:]...)...)
+	f.setPositions(order)
+}
+
+// Renumber set the positions of the elements to 1, 2, 3... keeping its order.
+func (f *Form) Renumber() {
+
+	order := []string{}
+	for _, field := range f.SortElements() {
+		order = append(order, field.Name)
+	}
+
+	f.setPositions(order)
+}
+
+// setPositions set the position of each field as its index in order plus one
+func (f *Form) setPositions(order []string) {
+	for i, fieldName := range order {
+		field := f.Elements[fieldName]
+		field.Position = i + 1
+		f.Elements[fieldName] = field
+	}
+}
+
 // NewRow insert a new row shortcut.
 func (f *Form) NewRow(rowName string) {
 	f.NewElement("row", rowName, "")
@@ -305,9 +695,7 @@ func (f *Form) NewButton(buttonName string) {
 // SetID set/change the ID to the field.
 func (f *Form) SetID(fieldName string, id string) {
 
-	// fieldID remove spaces and convert un lowercase
-	id = strings.ToLower(id)
-	id = strings.Replace(id, " ", "", -1)
+	id = normalizeName(id)
 
 	field := f.Elements[fieldName]
 	field.ID = id
@@ -329,7 +717,14 @@ func (f *Form) AddClass(fieldName string, class string) {
 }
 
 // AddCSS add a CSS value (in the form of option-value - e.g.: color - red).
+// Invalid properties and values that can run scripts are registered in the form log.
 func (f *Form) AddCSS(fieldName string, key, value string) {
+
+	if err := checkCSS(key, value); err != nil {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Unsafe CSS", Err: err})
+		return
+	}
+
 	f.Elements[fieldName].CSS[key] = value
 }
 
@@ -347,6 +742,20 @@ func (f *Form) SetOptions(fieldName string, options []OptionItem) {
 	f.Elements[fieldName] = field
 }
 
+// SetValues set/change the selected values of a multiselect or checkboxgroup.
+func (f *Form) SetValues(fieldName string, values []string) {
+	field := f.Elements[fieldName]
+	field.Values = values
+	f.Elements[fieldName] = field
+}
+
+// SetDisabled set/change the disabled state of a fieldset.
+func (f *Form) SetDisabled(fieldName string, disabled bool) {
+	field := f.Elements[fieldName]
+	field.Disabled = disabled
+	f.Elements[fieldName] = field
+}
+
 // SetPlaceHolder set the placeholder text to the input.
 func (f *Form) SetPlaceHolder(fieldName string, placeholder string) {
 	field := f.Elements[fieldName]
@@ -361,8 +770,58 @@ func (f *Form) SetHelpText(fieldName string, helptext string) {
 	f.Elements[fieldName] = field
 }
 
+// SetRichLabel set HTML content as label (the text of label elements), only links,
+// emphasis and lists are kept, see SanitizeHTML.
+func (f *Form) SetRichLabel(fieldName string, content template.HTML) {
+	field := f.Elements[fieldName]
+	field.RichLabel = content
+	f.Elements[fieldName] = field
+}
+
+// SetRichHelpText set HTML content as help-text, sanitized like SetRichLabel.
+func (f *Form) SetRichHelpText(fieldName string, content template.HTML) {
+	field := f.Elements[fieldName]
+	field.RichHelpText = content
+	f.Elements[fieldName] = field
+}
+
+// SetMin set the min attribute of number, range and date/time inputs.
+func (f *Form) SetMin(fieldName string, min string) {
+	field := f.Elements[fieldName]
+	field.Min = min
+	f.Elements[fieldName] = field
+}
+
+// SetMax set the max attribute of number, range and date/time inputs.
+func (f *Form) SetMax(fieldName string, max string) {
+	field := f.Elements[fieldName]
+	field.Max = max
+	f.Elements[fieldName] = field
+}
+
+// SetStep set the step attribute of number, range and date/time inputs.
+func (f *Form) SetStep(fieldName string, step string) {
+	field := f.Elements[fieldName]
+	field.Step = step
+	f.Elements[fieldName] = field
+}
+
+// SetPattern set the pattern attribute (regular expression) of text-like inputs.
+func (f *Form) SetPattern(fieldName string, pattern string) {
+	field := f.Elements[fieldName]
+	field.Pattern = pattern
+	f.Elements[fieldName] = field
+}
+
 // AddParams add a Param value (in the form of option-value - e.g.: maxlength - 15).
+// Invalid names, event handlers (onclick...), style and script URLs are registered in the form log.
 func (f *Form) AddParams(fieldName string, key, value string) {
+
+	if err := checkAttribute(key, value); err != nil {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: fieldName, Message: "Unsafe Attribute", Err: err})
+		return
+	}
+
 	f.Elements[fieldName].Params[key] = value
 }
 
@@ -373,10 +832,15 @@ func (f *Form) AddGroupClass(fieldName string, class string) {
 	f.Elements[fieldName] = field
 }
 
-// LogOutput display the Log
-func LogOutput(format string) string {
+// Errors returns the errors registered while building the form.
+func (f *Form) Errors() []ErrorItem {
+	return append([]ErrorItem{}, f.errors...)
+}
+
+// LogOutput display the Log of the form
+func (f *Form) LogOutput(format string) string {
 	text := ""
-	for _, logField := range logForm {
+	for _, logField := range f.errors {
 		switch format {
 		case "return":
 			text += logField.RelatedTo + " : " + logField.Message + "\n\r"
@@ -0,0 +1,134 @@
+package goform
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testForm returns a form with elements of every type, nested fieldsets and option providers
+func testForm(style string) *Form {
+
+	form := Create("order", "POST", "/order")
+	form.SetTemplateStyle(style)
+
+	form.NewElement("text", "customer", "Jane")
+	form.SetLabel("customer", "Customer")
+	form.SetPlaceHolder("customer", "Your name")
+	form.AddClass("customer", "wide")
+	form.AddRule("customer", Required("The customer is required"))
+
+	form.NewFieldset("address", "Address")
+	form.NewElement("textarea", "street", "")
+	form.SetHelpText("street", "Street and number")
+	form.AddToSet("street", "address")
+	form.NewElement("select", "country", "JP")
+	form.SetOptionProvider("country", OptionProviderFunc(func(ctx context.Context) ([]OptionItem, error) {
+		return []OptionItem{{Key: "ES", Value: "Spain"}, {Key: "JP", Value: "Japan"}}, nil
+	}))
+	form.AddToSet("country", "address")
+
+	form.NewFieldset("extra", "Extra")
+	form.AddToSet("extra", "address")
+	form.NewElement("radio", "size", "l")
+	form.SetOptionProvider("size", CacheOptions(OptionProviderFunc(func(ctx context.Context) ([]OptionItem, error) {
+		return []OptionItem{{Key: "s", Value: "Small"}, {Key: "l", Value: "Large"}}, nil
+	}), time.Minute, nil))
+	form.NewElement("checkbox", "gift", "yes")
+	form.SetChecked("gift", true)
+	form.AddToSet("gift", "extra")
+	form.NewElement("checkboxgroup", "extras", "")
+	form.SetOptions("extras", []OptionItem{{Key: "wrap", Value: "Gift wrap"}, {Key: "fast", Value: "Fast delivery"}})
+	form.SetValues("extras", []string{"fast"})
+	form.AddToSet("extras", "extra")
+	form.NewElement("hidden", "id", "7")
+
+	item := Create("item", "POST", "")
+	item.NewElement("text", "product", "")
+	item.NewElement("number", "quantity", "1")
+	item.NewElement("select", "size", "")
+	item.SetOptionProvider("size", OptionProviderFunc(func(ctx context.Context) ([]OptionItem, error) {
+		return []OptionItem{{Key: "s", Value: "Small"}, {Key: "l", Value: "Large"}}, nil
+	}))
+	form.NewRepeater("items", item, 2, 5)
+	form.Populate(map[string][]string{"items[0].product": {"Book"}, "items[0].size": {"l"}})
+
+	form.NewElement("submit", "send", "Send")
+
+	return form
+}
+
+func TestRenderConcurrent(t *testing.T) {
+
+	for _, style := range []string{"html", "bootstrap5"} {
+		t.Run(style, func(t *testing.T) {
+
+			form := testForm(style)
+			want, err := form.RenderString()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, text := range []string{`name="street"`, `value="JP"`, "Large", `name="gift"`, "Extra", "Fast delivery", `name="items[0].product"`, `value="Book"`, "Send"} {
+				if !strings.Contains(want, text) {
+					t.Fatalf("render without %s:\n%s", text, want)
+				}
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan string, 64)
+			for i := 0; i < 32; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 10; j++ {
+						got, err := form.RenderString()
+						if err != nil {
+							errs <- err.Error()
+							return
+						}
+						if got != want {
+							errs <- "output differs between renders"
+							return
+						}
+						if _, err := form.ElementsHTML(); err != nil {
+							errs <- err.Error()
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRenderDoesNotModifyForm(t *testing.T) {
+
+	form := testForm("bootstrap5")
+	first, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if form.FormText != "" || len(form.FormTemplates) != 0 {
+		t.Error("render stored the output in the form")
+	}
+	if len(form.Elements["country"].Options) != 0 {
+		t.Error("render stored the provider options in the form")
+	}
+
+	second, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("output differs between renders")
+	}
+}
@@ -0,0 +1,107 @@
+package goform
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OptionProvider provides the options of a select, radio, multiselect, checkboxgroup
+// or datalist, resolved every time the form is rendered or validated.
+type OptionProvider interface {
+	Options(ctx context.Context) ([]OptionItem, error)
+}
+
+// OptionProviderFunc adapter to use a function as OptionProvider.
+type OptionProviderFunc func(ctx context.Context) ([]OptionItem, error)
+
+// Options calls fn(ctx).
+func (fn OptionProviderFunc) Options(ctx context.Context) ([]OptionItem, error) {
+	return fn(ctx)
+}
+
+// SetOptionProvider set/change the provider of the options of the field,
+// the provider options replace the static Options.
+func (f *Form) SetOptionProvider(fieldName string, provider OptionProvider) {
+	field := f.Elements[fieldName]
+	field.OptionProvider = provider
+	f.Elements[fieldName] = field
+}
+
+// resolveOptions returns the field with the options of its provider
+func resolveOptions(ctx context.Context, field Field) (Field, error) {
+
+	if field.OptionProvider == nil {
+		return field, nil
+	}
+
+	options, err := field.OptionProvider.Options(ctx)
+	if err != nil {
+		return field, fmt.Errorf("goform: options of %s: %w", field.Name, err)
+	}
+	field.Options = options
+
+	return field, nil
+}
+
+// cachedOptions structure, OptionProvider that keeps the options for a while.
+type cachedOptions struct {
+	provider OptionProvider
+	ttl      time.Duration
+	key      func(ctx context.Context) string
+	mutex    sync.Mutex
+	entries  map[string]cachedOptionsEntry
+}
+
+type cachedOptionsEntry struct {
+	options []OptionItem
+	expires time.Time
+}
+
+// CacheOptions returns an OptionProvider that keeps the options of provider during ttl.
+// key returns the cache key of the request (e.g. the tenant ID stored in the context),
+// nil to share the same options in all the requests. Errors are not cached.
+func CacheOptions(provider OptionProvider, ttl time.Duration, key func(ctx context.Context) string) OptionProvider {
+	return &cachedOptions{
+		provider: provider,
+		ttl:      ttl,
+		key:      key,
+		entries:  make(map[string]cachedOptionsEntry),
+	}
+}
+
+// Options returns the cached options or the options of the provider.
+func (c *cachedOptions) Options(ctx context.Context) ([]OptionItem, error) {
+
+	key := ""
+	if c.key != nil {
+		key = c.key(ctx)
+	}
+
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	c.mutex.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.options, nil
+	}
+
+	options, err := c.provider.Options(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	c.mutex.Lock()
+	// Remove the expired entries of other keys
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedOptionsEntry{options, now.Add(c.ttl)}
+	c.mutex.Unlock()
+
+	return options, nil
+}
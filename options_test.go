@@ -0,0 +1,144 @@
+package goform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tenantKey context key of the tenant used by the provider tests
+type tenantKey struct{}
+
+// countingProvider returns the options of the tenant of the context, or err
+type countingProvider struct {
+	calls int32
+	err   error
+}
+
+func (p *countingProvider) Options(ctx context.Context) ([]OptionItem, error) {
+
+	atomic.AddInt32(&p.calls, 1)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return []OptionItem{{Key: tenant + "1", Value: "Option of " + tenant}}, nil
+}
+
+// tenantContext returns a context with the tenant
+func tenantContext(tenant string) context.Context {
+	return context.WithValue(context.Background(), tenantKey{}, tenant)
+}
+
+func TestCacheOptionsTTL(t *testing.T) {
+
+	provider := &countingProvider{}
+	cached := CacheOptions(provider, 50*time.Millisecond, nil)
+
+	for i := 0; i < 3; i++ {
+		options, err := cached.Options(tenantContext("a"))
+		if err != nil || len(options) != 1 || options[0].Key != "a1" {
+			t.Fatalf("options %v, error %v", options, err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want 1", provider.calls)
+	}
+
+	// Without key the options are shared by all the requests
+	if options, _ := cached.Options(tenantContext("b")); options[0].Key != "a1" {
+		t.Errorf("options %v, want the cached ones", options)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if options, _ := cached.Options(tenantContext("b")); options[0].Key != "b1" || provider.calls != 2 {
+		t.Errorf("after the ttl: options %v, provider called %d times, want 2", options, provider.calls)
+	}
+}
+
+func TestCacheOptionsKey(t *testing.T) {
+
+	provider := &countingProvider{}
+	cached := CacheOptions(provider, time.Minute, func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return tenant
+	})
+
+	for _, tenant := range []string{"a", "b", "a", "b", "c"} {
+		options, err := cached.Options(tenantContext(tenant))
+		if err != nil || options[0].Key != tenant+"1" {
+			t.Errorf("tenant %s: options %v, error %v", tenant, options, err)
+		}
+	}
+	if provider.calls != 3 {
+		t.Errorf("provider called %d times, want once by key", provider.calls)
+	}
+}
+
+func TestCacheOptionsErrors(t *testing.T) {
+
+	failure := errors.New("database down")
+	provider := &countingProvider{err: failure}
+	cached := CacheOptions(provider, time.Minute, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Options(context.Background()); !errors.Is(err, failure) {
+			t.Errorf("error %v, want %v", err, failure)
+		}
+	}
+
+	provider.err = nil
+	if options, err := cached.Options(tenantContext("a")); err != nil || len(options) != 1 {
+		t.Errorf("after the error: options %v, error %v", options, err)
+	}
+	if provider.calls != 3 {
+		t.Errorf("provider called %d times, want 3 (errors are not cached)", provider.calls)
+	}
+}
+
+func TestOptionProviderRender(t *testing.T) {
+
+	provider := &countingProvider{}
+	form := Create("trip", "POST", "/trip")
+	form.SetTemplateStyle("html")
+	form.NewElement("select", "city", "")
+	form.SetOptionProvider("city", provider)
+	form.AddRule("city", OneOfOptions("Choose a valid city"))
+
+	buf := new(bytes.Buffer)
+	if err := form.RenderContext(tenantContext("a"), buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `value="a1"`) {
+		t.Errorf("render without the provider options:\n%s", buf)
+	}
+
+	// The options are resolved on every render and validation, not stored in the form
+	buf.Reset()
+	if err := form.RenderContext(tenantContext("b"), buf); err != nil || !strings.Contains(buf.String(), `value="b1"`) {
+		t.Errorf("second render without the options of b, error %v:\n%s", err, buf)
+	}
+	if len(form.Elements["city"].Options) != 0 {
+		t.Error("render stored the provider options in the form")
+	}
+	if errs := form.ValidateValues(url.Values{"city": {"1"}}); len(errs) != 0 {
+		t.Errorf("option of the background context: errors %v", errs)
+	}
+	if errs := form.ValidateValues(url.Values{"city": {"a1"}}); len(errs) != 1 {
+		t.Errorf("option of other context: errors %v", errs)
+	}
+
+	provider.err = errors.New("database down")
+	if err := form.RenderContext(context.Background(), new(bytes.Buffer)); !errors.Is(err, provider.err) {
+		t.Errorf("render error %v, want the provider error", err)
+	}
+	if errs := form.ValidateValues(url.Values{"city": {"1"}}); len(errs) != 1 || !errors.Is(errs[0].Err, provider.err) {
+		t.Errorf("validate errors %v, want the provider error", errs)
+	}
+}
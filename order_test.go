@@ -0,0 +1,189 @@
+package goform
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// elementOrder returns the names of the form elements sorted by position
+func elementOrder(form *Form) []string {
+	names := []string{}
+	for _, field := range form.SortElements() {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// orderForm returns a form with the text elements a, b, c and d
+func orderForm() *Form {
+	form := Create("order", "POST", "/")
+	for _, name := range []string{"a", "b", "c", "d"} {
+		form.NewElement("text", name, "")
+	}
+	return form
+}
+
+func TestMoveTo(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		position int
+		want     []string
+	}{
+		{"c", 1, []string{"c", "a", "b", "d"}},
+		{"a", 3, []string{"b", "c", "a", "d"}},
+		{"b", 4, []string{"a", "c", "d", "b"}},
+		{"b", 0, []string{"b", "a", "c", "d"}},
+		{"b", -5, []string{"b", "a", "c", "d"}},
+		{"a", 99, []string{"b", "c", "d", "a"}},
+	}
+
+	for _, tt := range tests {
+		form := orderForm()
+		form.MoveTo(tt.name, tt.position)
+
+		if got := elementOrder(form); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MoveTo(%q, %d) = %v, want %v", tt.name, tt.position, got, tt.want)
+		}
+		for i, field := range form.SortElements() {
+			if field.Position != i+1 {
+				t.Errorf("MoveTo(%q, %d): %s at position %d, want %d", tt.name, tt.position, field.Name, field.Position, i+1)
+			}
+		}
+	}
+}
+
+func TestMoveToMissing(t *testing.T) {
+
+	form := orderForm()
+	form.MoveTo("missing", 1)
+
+	if errs := form.Errors(); len(errs) != 1 || !errors.Is(errs[0].Err, ErrFieldNotFound) {
+		t.Errorf("errors %v, want ErrFieldNotFound", errs)
+	}
+}
+
+func TestInsertBeforeAfter(t *testing.T) {
+
+	form := orderForm()
+	form.InsertBefore("a", "text", "first", "")
+	form.InsertAfter("b", "text", "After B", "")
+	form.InsertAfter("d", "text", "last", "")
+
+	want := []string{"first", "a", "b", "afterb", "c", "d", "last"}
+	if got := elementOrder(form); !reflect.DeepEqual(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+	if errs := form.Errors(); len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
+
+func TestInsertErrors(t *testing.T) {
+
+	form := orderForm()
+	form.InsertBefore("missing", "text", "e", "")
+	form.InsertAfter("a", "text", "b", "")
+	form.InsertAfter("a", "unknown", "f", "")
+
+	want := []error{ErrFieldNotFound, ErrDuplicateField, ErrUnknownType}
+	errs := form.Errors()
+	if len(errs) != len(want) {
+		t.Fatalf("errors %v, want %v", errs, want)
+	}
+	for i, err := range want {
+		if !errors.Is(errs[i].Err, err) {
+			t.Errorf("error %d is %v, want %v", i, errs[i].Err, err)
+		}
+	}
+
+	if got := elementOrder(form); !reflect.DeepEqual(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("order %v, want the elements unchanged", got)
+	}
+}
+
+func TestRemoveElement(t *testing.T) {
+
+	form := orderForm()
+	form.RemoveElement("b")
+	form.RemoveElement("missing")
+
+	if got := elementOrder(form); !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Errorf("order %v, want [a c d]", got)
+	}
+	if got := form.Elements["d"].Position; got != 3 {
+		t.Errorf("d at position %d, want 3", got)
+	}
+	if got := form.FormTypes["text"]; got != 3 {
+		t.Errorf("FormTypes[text] = %d, want 3", got)
+	}
+	if errs := form.Errors(); len(errs) != 1 || !errors.Is(errs[0].Err, ErrFieldNotFound) {
+		t.Errorf("errors %v, want ErrFieldNotFound", errs)
+	}
+
+	form.NewElement("textarea", "notes", "")
+	form.RemoveElement("notes")
+	if _, ok := form.FormTypes["textarea"]; ok {
+		t.Error("FormTypes keeps a type without elements")
+	}
+}
+
+func TestRemoveFieldset(t *testing.T) {
+
+	form := Create("address", "POST", "/")
+	form.NewFieldset("person", "Person")
+	form.NewFieldset("address", "Address")
+	form.AddToSet("address", "person")
+	form.NewElement("text", "street", "")
+	form.AddToSet("street", "address")
+	form.NewElement("text", "city", "")
+	form.AddToSet("city", "address")
+
+	form.RemoveElement("address")
+
+	if _, ok := form.Elements["address"]; ok {
+		t.Fatal("fieldset not removed")
+	}
+	for _, name := range []string{"street", "city"} {
+		if got := form.Elements[name].Set; got != "person" {
+			t.Errorf("%s in set %q, want person", name, got)
+		}
+	}
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`name="street"`, `name="city"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("render lost %s", want)
+		}
+	}
+}
+
+func TestRenumberTies(t *testing.T) {
+
+	form := orderForm()
+	for _, name := range []string{"d", "b", "c", "a"} {
+		field := form.Elements[name]
+		field.Position = 7
+		form.Elements[name] = field
+	}
+	field := form.Elements["c"]
+	field.Position = 2
+	form.Elements["c"] = field
+
+	form.Renumber()
+
+	want := []string{"c", "a", "b", "d"}
+	if got := elementOrder(form); !reflect.DeepEqual(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+	for i, name := range want {
+		if got := form.Elements[name].Position; got != i+1 {
+			t.Errorf("%s at position %d, want %d", name, got, i+1)
+		}
+	}
+}
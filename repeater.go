@@ -0,0 +1,212 @@
+package goform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// repeaterIndex placeholder of the row index in the template of new rows
+const repeaterIndex = "__index__"
+
+// ErrRowCount the number of submitted rows is out of the min and max limits of the repeater
+var ErrRowCount = errors.New("goform: number of rows out of range")
+
+// ErrInvalidGroup the group of the repeater is missing or has signedhidden elements,
+// these can not be signed by row
+var ErrInvalidGroup = errors.New("goform: invalid repeater group")
+
+// Repeater structure, configuration of a repeatable group of elements.
+// Group holds the elements of one row, Min and Max limit the number of rows (0 for no limit).
+type Repeater struct {
+	Group *Form
+	Min   int
+	Max   int
+}
+
+// NewRepeater insert a repeatable group of elements, each row has the elements of group
+// with indexed names (e.g. skills[0].name) and the user can add and remove rows.
+// Errors are registered in the form log, groups with signedhidden elements are rejected.
+func (f *Form) NewRepeater(repeaterName string, group *Form, min int, max int) string {
+
+	if group != nil && group.FormTypes["signedhidden"] > 0 {
+		repeaterName = normalizeName(repeaterName)
+		f.errors = append(f.errors, ErrorItem{RelatedTo: repeaterName, Message: "Signed Elements In Repeater", Err: ErrInvalidGroup})
+		return repeaterName
+	}
+
+	repeaterName, err := f.AddElement("repeater", repeaterName, "")
+	if err != nil {
+		f.logElementError("repeater", repeaterName, err)
+		return repeaterName
+	}
+
+	field := f.Elements[repeaterName]
+	field.Repeater = &Repeater{group, min, max}
+	f.Elements[repeaterName] = field
+
+	return repeaterName
+}
+
+// Rows returns the rows of the repeater, after Bind or Populate these are the submitted rows.
+func (f *Form) Rows(fieldName string) []map[string]string {
+	return append([]map[string]string{}, f.Elements[fieldName].Rows...)
+}
+
+// ParseRepeater returns the submitted rows of the repeater name, one map by row with the
+// values of its elements, sorted by the row index (removed rows leave no gaps).
+func ParseRepeater(values url.Values, name string) []map[string]string {
+
+	key := regexp.MustCompile(`^` + regexp.QuoteMeta(name) + `\[(\d+)\]\.(.+)$`)
+	indexed := map[int]map[string]string{}
+
+	for k, v := range values {
+		match := key.FindStringSubmatch(k)
+		if match == nil || len(v) == 0 {
+			continue
+		}
+		index, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if indexed[index] == nil {
+			indexed[index] = map[string]string{}
+		}
+		indexed[index][match[2]] = v[0]
+	}
+
+	indexes := make([]int, 0, len(indexed))
+	for index := range indexed {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	rows := make([]map[string]string, 0, len(indexes))
+	for _, index := range indexes {
+		rows = append(rows, indexed[index])
+	}
+
+	return rows
+}
+
+// renderRepeater returns the field with the rendered rows and the template of new rows
+func (f *Form) renderRepeater(formTemplates map[string]*template.Template, field Field) (Field, error) {
+
+	if field.Repeater == nil || field.Repeater.Group == nil {
+		return field, fmt.Errorf("%w: %s has no group", ErrInvalidGroup, field.Name)
+	}
+	if field.Repeater.Group.FormTypes["signedhidden"] > 0 {
+		return field, fmt.Errorf("%w: %s has signedhidden elements", ErrInvalidGroup, field.Name)
+	}
+
+	count := len(field.Rows)
+	if count < field.Repeater.Min {
+		count = field.Repeater.Min
+	}
+	if field.Repeater.Max > 0 && count > field.Repeater.Max {
+		count = field.Repeater.Max
+	}
+
+	field.RowsHTML = []template.HTML{}
+	for i := 0; i < count; i++ {
+		row := map[string]string{}
+		if i < len(field.Rows) {
+			row = field.Rows[i]
+		}
+
+		buf := new(bytes.Buffer)
+		if err := f.renderRepeaterRow(buf, formTemplates, field, strconv.Itoa(i), row); err != nil {
+			return field, err
+		}
+		field.RowsHTML = append(field.RowsHTML, template.HTML(buf.String()))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := f.renderRepeaterRow(buf, formTemplates, field, repeaterIndex, nil); err != nil {
+		return field, err
+	}
+	field.RowTemplate = template.HTML(buf.String())
+
+	return field, nil
+}
+
+// renderRepeaterRow apply the template to each element of the group with the indexed names
+func (f *Form) renderRepeaterRow(w io.Writer, formTemplates map[string]*template.Template, field Field, index string, row map[string]string) error {
+
+	group := field.Repeater.Group
+
+	for _, itemForm := range group.SortElements() {
+
+		if len(itemForm.GroupClass) == 0 && len(group.GroupClass) > 0 {
+			itemForm.GroupClass = group.GroupClass
+		}
+
+		itemForm, err := resolveOptions(f.context(), itemForm)
+		if err != nil {
+			return err
+		}
+
+		if value, ok := row[itemForm.Name]; ok {
+			if itemForm.FieldType == "checkbox" {
+				itemForm.Checked = value == itemForm.Value || (itemForm.Value == "" && value == "on")
+			} else {
+				itemForm.Value = value
+			}
+		}
+
+		itemForm.Name = field.Name + "[" + index + "]." + itemForm.Name
+		itemForm.ID = field.ID + "_" + index + "_" + itemForm.ID
+
+		if err := formTemplates[itemForm.FieldType].Execute(w, itemForm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRows check the number of submitted rows of the repeater and the group rules in each row,
+// the errors of a row are related to the indexed names of its elements (e.g. skills[0].name).
+func validateRows(ctx context.Context, field Field, values url.Values) []ErrorItem {
+
+	items := []ErrorItem{}
+	if field.Repeater == nil || field.Repeater.Group == nil {
+		return items
+	}
+
+	repeater := field.Repeater
+	if repeater.Group.FormTypes["signedhidden"] > 0 {
+		return append(items, ErrorItem{RelatedTo: field.Name, Message: "Signed Elements In Repeater", Err: ErrInvalidGroup})
+	}
+
+	rows := ParseRepeater(values, field.Name)
+
+	if len(rows) < repeater.Min {
+		message := fmt.Sprintf("At least %d rows are required", repeater.Min)
+		items = append(items, ErrorItem{RelatedTo: field.Name, Message: message, Err: ErrRowCount})
+	}
+	if repeater.Max > 0 && len(rows) > repeater.Max {
+		message := fmt.Sprintf("At most %d rows are allowed", repeater.Max)
+		items = append(items, ErrorItem{RelatedTo: field.Name, Message: message, Err: ErrRowCount})
+	}
+
+	for i, row := range rows {
+		rowValues := url.Values{}
+		for name, value := range row {
+			rowValues.Set(name, value)
+		}
+		for _, item := range repeater.Group.validateValues(ctx, rowValues) {
+			item.RelatedTo = field.Name + "[" + strconv.Itoa(i) + "]." + item.RelatedTo
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,139 @@
+package goform
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// skillsForm returns a form with a repeater of skills, between 1 and 3 rows
+func skillsForm() *Form {
+
+	skill := Create("skill", "", "")
+	skill.NewElement("text", "name", "")
+	skill.AddRule("name", Required("The name is required"))
+	skill.NewElement("select", "level", "")
+	skill.SetOptions("level", []OptionItem{{Key: "1", Value: "Junior"}, {Key: "2", Value: "Senior"}})
+	skill.AddRule("level", OneOfOptions("Choose a level"))
+	skill.NewElement("checkbox", "main", "")
+	skill.AddRule("main", Required("Mark the skill as main"))
+
+	form := Create("cv", "POST", "/")
+	form.NewRepeater("skills", skill, 1, 3)
+
+	return form
+}
+
+func TestParseRepeater(t *testing.T) {
+
+	values := url.Values{
+		"skills[4].name":  {"Go"},
+		"skills[0].name":  {"SQL"},
+		"skills[0].level": {"2"},
+		"skills.name":     {"ignored"},
+		"other[1].name":   {"ignored"},
+	}
+
+	want := []map[string]string{{"name": "SQL", "level": "2"}, {"name": "Go"}}
+	if got := ParseRepeater(values, "skills"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRepeater = %v, want %v", got, want)
+	}
+}
+
+func TestValidateRepeaterRows(t *testing.T) {
+
+	form := skillsForm()
+	values := url.Values{
+		"skills[0].name":  {"Go"},
+		"skills[0].level": {"2"},
+		"skills[0].main":  {"on"},
+		"skills[3].name":  {""},
+		"skills[3].level": {"9"},
+	}
+
+	errs := form.Validate(postValues("/", values))
+
+	want := map[string]string{
+		"skills[1].name":  "The name is required",
+		"skills[1].level": "Choose a level",
+		"skills[1].main":  "Mark the skill as main",
+	}
+	got := map[string]string{}
+	for _, item := range errs {
+		got[item.RelatedTo] = item.Message
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("errors %v, want %v", got, want)
+	}
+}
+
+func TestValidateRepeaterRowCount(t *testing.T) {
+
+	tests := []struct {
+		rows    int
+		wantErr bool
+	}{
+		{0, true},
+		{1, false},
+		{3, false},
+		{4, true},
+	}
+
+	for _, tt := range tests {
+		values := url.Values{}
+		for i := 0; i < tt.rows; i++ {
+			prefix := "skills[" + strconv.Itoa(i) + "]."
+			values.Set(prefix+"name", "Go")
+			values.Set(prefix+"level", "1")
+			values.Set(prefix+"main", "on")
+		}
+
+		errs := skillsForm().ValidateValues(values)
+
+		if !tt.wantErr {
+			if len(errs) != 0 {
+				t.Errorf("%d rows: unexpected errors %v", tt.rows, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || errs[0].RelatedTo != "skills" || !errors.Is(errs[0].Err, ErrRowCount) {
+			t.Errorf("%d rows: errors %v, want ErrRowCount", tt.rows, errs)
+		}
+	}
+}
+
+func TestRepeaterSignedHidden(t *testing.T) {
+
+	item := Create("item", "", "")
+	item.NewElement("text", "product", "")
+	item.NewElement("signedhidden", "price", "10")
+
+	form := Create("order", "POST", "/")
+	form.SetSigningKey([]byte("signing secret"))
+	form.NewRepeater("items", item, 1, 0)
+
+	if _, ok := form.Elements["items"]; ok {
+		t.Error("repeater with signedhidden elements added")
+	}
+	if errs := form.Errors(); len(errs) != 1 || !errors.Is(errs[0].Err, ErrInvalidGroup) {
+		t.Errorf("errors %v, want ErrInvalidGroup", errs)
+	}
+
+	// Signed elements added to the group later are neither rendered nor accepted
+	group := Create("item", "", "")
+	group.NewElement("text", "product", "")
+	form.NewRepeater("lines", group, 1, 0)
+	group.NewElement("signedhidden", "price", "10")
+
+	if _, err := form.RenderString(); !errors.Is(err, ErrInvalidGroup) {
+		t.Errorf("render error %v, want ErrInvalidGroup", err)
+	}
+
+	tampered := url.Values{"lines[0].product": {"Book"}, "lines[0].price": {"0"}}
+	errs := form.ValidateValues(tampered)
+	if len(errs) != 1 || errs[0].RelatedTo != "lines" || !errors.Is(errs[0].Err, ErrInvalidGroup) {
+		t.Errorf("tampered row: errors %v, want ErrInvalidGroup", errs)
+	}
+}
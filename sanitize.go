@@ -0,0 +1,194 @@
+package goform
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	// htmlToken comments and tags of the rich content, the text between them is escaped
+	htmlToken = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^<>]*>`)
+	// htmlTag parts of a tag: closing slash, name and attributes
+	htmlTag = regexp.MustCompile(`(?s)^<(/?)([a-zA-Z][a-zA-Z0-9]*)(.*?)/?>$`)
+	// htmlAttribute name and value (double quoted, single quoted or unquoted) of an attribute
+	htmlAttribute = regexp.MustCompile(`([a-zA-Z][-a-zA-Z0-9]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+)
+
+// allowedTags tags of the rich content and its allowed attributes
+var allowedTags = map[string]map[string]bool{
+	"a":      {"href": true, "title": true, "target": true},
+	"b":      {},
+	"strong": {},
+	"i":      {},
+	"em":     {},
+	"u":      {},
+	"s":      {},
+	"small":  {},
+	"mark":   {},
+	"code":   {},
+	"span":   {"title": true},
+	"p":      {},
+	"br":     {},
+	"ul":     {},
+	"ol":     {},
+	"li":     {},
+}
+
+// voidTags allowed tags without closing tag
+var voidTags = map[string]bool{"br": true}
+
+// droppedTags tags removed with its content
+var droppedTags = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "template": true, "textarea": true, "title": true}
+
+// SanitizeHTML returns the content with only the allowed tags: links (http, https, mailto
+// and relative), emphasis (b, strong, i, em, u, s, small, mark, code, span), paragraphs,
+// line breaks and lists. Other tags are removed keeping its text, except scripts, styles and
+// frames that are removed completely, the text is escaped and the open tags are closed.
+func SanitizeHTML(content string) template.HTML {
+
+	var out strings.Builder
+	var open []string
+	dropping := ""
+
+	text := func(s string) {
+		if dropping == "" {
+			out.WriteString(template.HTMLEscapeString(html.UnescapeString(s)))
+		}
+	}
+
+	last := 0
+	for _, loc := range htmlToken.FindAllStringIndex(content, -1) {
+
+		text(content[last:loc[0]])
+		last = loc[1]
+
+		match := htmlTag.FindStringSubmatch(content[loc[0]:loc[1]])
+		if match == nil {
+			continue // comment
+		}
+		closing, name := match[1] == "/", strings.ToLower(match[2])
+
+		// Skip everything until the closing tag of a dropped tag
+		if dropping != "" {
+			if closing && name == dropping {
+				dropping = ""
+			}
+			continue
+		}
+		if droppedTags[name] {
+			if !closing {
+				dropping = name
+			}
+			continue
+		}
+
+		attributes, ok := allowedTags[name]
+		if !ok {
+			continue
+		}
+
+		if closing {
+			// Close the tag and the tags opened inside it
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					for j := len(open) - 1; j >= i; j-- {
+						out.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		// A new item closes the previous one
+		if name == "li" && len(open) > 0 && open[len(open)-1] == "li" {
+			out.WriteString("</li>")
+			open = open[:len(open)-1]
+		}
+
+		out.WriteString("<" + name + sanitizeAttributes(name, attributes, match[3]) + ">")
+		if !voidTags[name] {
+			open = append(open, name)
+		}
+	}
+	text(content[last:])
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+
+	return template.HTML(out.String())
+}
+
+// sanitizeAttributes returns the allowed attributes of the tag with the values escaped,
+// links with unsafe URLs lose the href and links opened in other window get rel="noopener noreferrer"
+func sanitizeAttributes(tag string, allowed map[string]bool, text string) string {
+
+	var out strings.Builder
+	blank := false
+
+	for _, match := range htmlAttribute.FindAllStringSubmatch(text, -1) {
+
+		name := strings.ToLower(match[1])
+		value := html.UnescapeString(match[2] + match[3] + match[4])
+		if !allowed[name] {
+			continue
+		}
+
+		switch name {
+		case "href":
+			if !safeURL(value) {
+				continue
+			}
+		case "target":
+			if value != "_blank" {
+				continue
+			}
+			blank = true
+		}
+
+		out.WriteString(" " + name + `="` + template.HTMLEscapeString(value) + `"`)
+	}
+
+	if tag == "a" && blank {
+		out.WriteString(` rel="noopener noreferrer"`)
+	}
+
+	return out.String()
+}
+
+// safeURL returns true if the URL is relative or its scheme is http, https or mailto
+func safeURL(value string) bool {
+
+	value = strings.TrimSpace(value)
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok || strings.ContainsAny(scheme, "/?#") {
+		return !scriptURL(value)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+
+	return false
+}
+
+// LabelContent returns the sanitized RichLabel or the escaped Label, used by the templates.
+func (field Field) LabelContent() template.HTML {
+	if field.RichLabel != "" {
+		return SanitizeHTML(string(field.RichLabel))
+	}
+	return template.HTML(template.HTMLEscapeString(field.Label))
+}
+
+// HelpContent returns the sanitized RichHelpText or the escaped HelpText, used by the templates.
+func (field Field) HelpContent() template.HTML {
+	if field.RichHelpText != "" {
+		return SanitizeHTML(string(field.RichHelpText))
+	}
+	return template.HTML(template.HTMLEscapeString(field.HelpText))
+}
@@ -0,0 +1,77 @@
+package goform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"allowed tags", `<p>a <b>b</b> <em>c</em><br>d</p>`, `<p>a <b>b</b> <em>c</em><br>d</p>`},
+		{"text escaped", `1 < 2 & 3 > 2`, `1 &lt; 2 &amp; 3 &gt; 2`},
+		{"entities kept escaped", `&lt;script&gt;`, `&lt;script&gt;`},
+		{"safe link", `<a href="https://example.com/a?b=1&amp;c=2" title='say "hi"'>x</a>`, `<a href="https://example.com/a?b=1&amp;c=2" title="say &#34;hi&#34;">x</a>`},
+		{"relative link", `<a href="/path#top">x</a>`, `<a href="/path#top">x</a>`},
+		{"mailto link", `<a href="mailto:jane@example.com">x</a>`, `<a href="mailto:jane@example.com">x</a>`},
+		{"unquoted href", `<a href=https://example.com/a>x</a>`, `<a href="https://example.com/a">x</a>`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"unquoted javascript href", `<a href=javascript:alert(1)>x</a>`, `<a>x</a>`},
+		{"mixed case javascript href", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a>x</a>`},
+		{"entity encoded javascript href", `<a href="&#106;avascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"hex entity encoded javascript href", `<a href="&#x6A;avascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"tab inside javascript href", `<a href="java&#x09;script:alert(1)">x</a>`, `<a>x</a>`},
+		{"spaces before javascript href", `<a href="  javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"data href", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, `<a>x</a>`},
+		{"tag inside attribute value", `<a href="x<script>alert(1)</script>">x</a>`, `&lt;a href=&#34;x&#34;&gt;x`},
+		{"vbscript href", `<a href='vbscript:msgbox(1)'>x</a>`, `<a>x</a>`},
+		{"blank target", `<a href="https://example.com" target="_blank">x</a>`, `<a href="https://example.com" target="_blank" rel="noopener noreferrer">x</a>`},
+		{"other target", `<a href="https://example.com" target="_self">x</a>`, `<a href="https://example.com">x</a>`},
+		{"event handler", `<b onclick="alert(1)">x</b>`, `<b>x</b>`},
+		{"style attribute", `<span style="color: red" title="t">x</span>`, `<span title="t">x</span>`},
+		{"svg onload", `<svg onload=alert(1)><circle r="1"/></svg>ok`, `ok`},
+		{"img onerror", `<img src=x onerror=alert(1)>ok`, `ok`},
+		{"unknown tag keeps text", `<div><h1>Title</h1></div>`, `Title`},
+		{"script dropped", `<script>alert(1)</script>ok`, `ok`},
+		{"upper case script dropped", `<SCRIPT>alert(1)</SCRIPT>ok`, `ok`},
+		{"unclosed script dropped", `ok<script>alert(1)`, `ok`},
+		{"style dropped", `<style>body{display:none}</style>ok`, `ok`},
+		{"iframe dropped", `<iframe src="https://example.com">x</iframe>ok`, `ok`},
+		{"comment removed", `a<!-- <script>alert(1)</script> -->b`, `ab`},
+		{"unclosed b", `<b>bold`, `<b>bold</b>`},
+		{"unclosed nested tags", `<p><b><i>x`, `<p><b><i>x</i></b></p>`},
+		{"unclosed li", `<ul><li>one<li>two</ul>`, `<ul><li>one</li><li>two</li></ul>`},
+		{"misnested tags", `<b><i>x</b>y</i>`, `<b><i>x</i></b>y`},
+		{"stray closing tag", `</b>x</p>`, `x`},
+		{"broken tag", `<b x</b>`, `&lt;b x`},
+	}
+
+	for _, tt := range tests {
+		if got := string(SanitizeHTML(tt.content)); got != tt.want {
+			t.Errorf("%s: SanitizeHTML(%q) = %q, want %q", tt.name, tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestRichLabelSanitized(t *testing.T) {
+
+	form := Create("terms", "POST", "/terms")
+	form.NewElement("checkbox", "accept", "yes")
+	form.SetRichLabel("accept", `I accept the <a href="/terms" target="_blank">terms</a><script>alert(1)</script>`)
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(html, `<a href="/terms" target="_blank" rel="noopener noreferrer">terms</a>`) {
+		t.Errorf("rich label not rendered:\n%s", html)
+	}
+	if strings.Contains(html, "alert(1)") {
+		t.Errorf("script rendered:\n%s", html)
+	}
+}
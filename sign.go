@@ -0,0 +1,92 @@
+package goform
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSignature the signed value was altered or signed with other secret
+var ErrInvalidSignature = errors.New("goform: invalid signature")
+
+// signValue returns the value and its HMAC-SHA256 signature, both base64 encoded
+func signValue(secret []byte, value string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return encoded + "." + signature(secret, encoded)
+}
+
+// verifyValue returns the value signed with signValue or ErrInvalidSignature
+func verifyValue(secret []byte, signed string) (string, error) {
+
+	encoded, mac, ok := strings.Cut(signed, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(signature(secret, encoded))) {
+		return "", ErrInvalidSignature
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	return string(value), nil
+}
+
+// signature returns the HMAC-SHA256 of the value, base64 encoded
+func signature(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// deriveKey returns a 32 bytes key for the purpose, derived from the secret with HMAC-SHA256
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// newAEAD returns the AES-GCM cipher of the key, of 16, 24 or 32 bytes
+func newAEAD(key []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// sealAEAD returns the value encrypted with a random nonce, base64 encoded, data is
+// authenticated but not encrypted (e.g. the name of the field)
+func sealAEAD(aead cipher.AEAD, value string, data string) (string, error) {
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// openAEAD returns the value encrypted by sealAEAD with the same data, or ErrInvalidSignature
+func openAEAD(aead cipher.AEAD, sealed string, data string) (string, error) {
+
+	text, err := base64.RawURLEncoding.DecodeString(sealed)
+	size := aead.NonceSize()
+	if err != nil || len(text) < size {
+		return "", ErrInvalidSignature
+	}
+
+	value, err := aead.Open(nil, text[:size], text[size:], []byte(data))
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	return string(value), nil
+}
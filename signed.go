@@ -0,0 +1,84 @@
+package goform
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSigningKey the form has signedhidden elements and no signing or encryption key
+var ErrNoSigningKey = errors.New("goform: signedhidden element without signing key")
+
+// signedConfig structure, keys of the signedhidden elements
+type signedConfig struct {
+	secret []byte
+	aead   cipher.AEAD
+}
+
+// SetSigningKey set the secret of the HMAC signature of the signedhidden elements,
+// the submitted values are verified by Bind, Populate and Decode.
+func (f *Form) SetSigningKey(secret []byte) {
+	f.signed.secret = secret
+}
+
+// SetEncryptionKey encrypt the values of the signedhidden elements with AES-GCM instead of
+// signing them, so the user can not read them either. The key must have 16, 24 or 32 bytes,
+// other sizes are registered in the form log.
+func (f *Form) SetEncryptionKey(key []byte) {
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: "", Message: "Invalid Encryption Key", Err: err})
+		return
+	}
+	f.signed.aead = aead
+}
+
+// sealValue returns the value signed or encrypted, bound to the names of the form and the field
+func (f *Form) sealValue(name string, value string) (string, error) {
+
+	if f.signed.aead != nil {
+		return sealAEAD(f.signed.aead, value, f.sealedName(name))
+	}
+
+	if len(f.signed.secret) == 0 {
+		return "", ErrNoSigningKey
+	}
+
+	return signValue(f.signed.secret, f.sealedName(name)+"\x00"+value), nil
+}
+
+// openValue returns the value sealed by sealValue for the field, or ErrInvalidSignature if altered
+func (f *Form) openValue(name string, sealed string) (string, error) {
+
+	if f.signed.aead != nil {
+		return openAEAD(f.signed.aead, sealed, f.sealedName(name))
+	}
+
+	if len(f.signed.secret) == 0 {
+		return "", ErrNoSigningKey
+	}
+
+	text, err := verifyValue(f.signed.secret, sealed)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := f.sealedName(name) + "\x00"
+	if !strings.HasPrefix(text, prefix) {
+		return "", ErrInvalidSignature
+	}
+
+	return strings.TrimPrefix(text, prefix), nil
+}
+
+// sealedName returns the names of the form and the field the sealed values are bound to
+func (f *Form) sealedName(name string) string {
+	return f.Name + "\x00" + name
+}
+
+// openError returns the error of an altered signedhidden element
+func openError(name string, err error) ErrorItem {
+	return ErrorItem{RelatedTo: name, Message: "Altered Value", Err: fmt.Errorf("goform: field %s: %w", name, err)}
+}
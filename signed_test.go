@@ -0,0 +1,147 @@
+package goform
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// signedForm returns a form with the signedhidden element "price" and the text element "note"
+func signedForm(encrypted bool) *Form {
+
+	form := Create("order", "POST", "/order")
+	if encrypted {
+		form.SetEncryptionKey([]byte("0123456789abcdef0123456789abcdef"))
+	} else {
+		form.SetSigningKey([]byte("signing secret"))
+	}
+
+	form.NewElement("signedhidden", "price", "10")
+	form.AddRule("price", Required("The price is required"))
+	form.AddRule("price", Range(0, 100, "The price must be between 0 and 100"))
+	form.NewElement("signedhidden", "discount", "5")
+	form.NewElement("text", "note", "")
+
+	return form
+}
+
+func TestSignedValues(t *testing.T) {
+
+	for _, encrypted := range []bool{false, true} {
+
+		form := signedForm(encrypted)
+		price, err := form.sealValue("price", "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		discount, err := form.sealValue("discount", "5")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Same key, other form
+		other := signedForm(encrypted)
+		other.Name = "quote"
+		otherPrice, err := other.sealValue("price", "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name   string
+			values url.Values
+			valid  bool
+		}{
+			{"valid", url.Values{"price": {price}, "discount": {discount}}, true},
+			{"tampered value", url.Values{"price": {price + "x"}, "discount": {discount}}, false},
+			{"plain value", url.Values{"price": {"10"}, "discount": {discount}}, false},
+			{"empty value", url.Values{"price": {""}, "discount": {discount}}, false},
+			{"swapped names", url.Values{"price": {discount}, "discount": {price}}, false},
+			{"other form", url.Values{"price": {otherPrice}, "discount": {discount}}, false},
+		}
+
+		for _, tt := range tests {
+
+			_, err := signedForm(encrypted).openValue("price", tt.values.Get("price"))
+			if tt.valid != (err == nil) {
+				t.Errorf("encrypted %v, %s: openValue error %v", encrypted, tt.name, err)
+			}
+
+			errs := signedForm(encrypted).ValidateValues(tt.values)
+			if tt.valid != (len(errs) == 0) {
+				t.Errorf("encrypted %v, %s: ValidateValues errors %v", encrypted, tt.name, errs)
+			}
+			if !tt.valid && (len(errs) == 0 || !errors.Is(errs[0].Err, ErrInvalidSignature)) {
+				t.Errorf("encrypted %v, %s: errors %v, want ErrInvalidSignature", encrypted, tt.name, errs)
+			}
+
+			err = signedForm(encrypted).Bind(postValues("/order", tt.values))
+			if tt.valid != (err == nil) || (!tt.valid && !errors.Is(err, ErrInvalidSignature)) {
+				t.Errorf("encrypted %v, %s: Bind error %v", encrypted, tt.name, err)
+			}
+
+			var dst struct {
+				Price    int
+				Discount int
+			}
+			err = signedForm(encrypted).Decode(postValues("/order", tt.values), &dst)
+			if tt.valid != (err == nil) || (!tt.valid && !errors.Is(err, ErrInvalidSignature)) {
+				t.Errorf("encrypted %v, %s: Decode error %v", encrypted, tt.name, err)
+			}
+			if tt.valid && (dst.Price != 10 || dst.Discount != 5) {
+				t.Errorf("encrypted %v, %s: decoded %+v", encrypted, tt.name, dst)
+			}
+		}
+	}
+}
+
+func TestValidateSignedRules(t *testing.T) {
+
+	form := signedForm(false)
+	price, _ := form.sealValue("price", "500")
+	discount, _ := form.sealValue("discount", "5")
+
+	errs := form.ValidateValues(url.Values{"price": {price}, "discount": {discount}})
+	if len(errs) != 1 || errs[0].Message != "The price must be between 0 and 100" {
+		t.Errorf("errors %v, want the range error of the verified value", errs)
+	}
+}
+
+func TestRenderSignedValue(t *testing.T) {
+
+	form := signedForm(false)
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(html, `value="10"`) {
+		t.Errorf("unsigned value in the form:\n%s", html)
+	}
+
+	form = Create("order", "POST", "/order")
+	form.NewElement("signedhidden", "price", "10")
+	if _, err := form.RenderString(); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("render without key: error %v, want ErrNoSigningKey", err)
+	}
+}
+
+func TestBindFirstAlteredValue(t *testing.T) {
+
+	values := url.Values{"price": {"10"}, "discount": {"5"}}
+
+	for i := 0; i < 20; i++ {
+		err := signedForm(false).Bind(postValues("/order", values))
+		if err == nil || !strings.Contains(err.Error(), "field price") {
+			t.Fatalf("Bind error %v, want the error of price", err)
+		}
+	}
+
+	form := signedForm(false)
+	form.Populate(values)
+	errs := form.Errors()
+	if len(errs) != 2 || errs[0].RelatedTo != "price" || errs[1].RelatedTo != "discount" {
+		t.Errorf("errors %v, want price and discount in order", errs)
+	}
+}
@@ -0,0 +1,262 @@
+package goform
+
+import (
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	fileHeaderType = reflect.TypeOf(&multipart.FileHeader{})
+)
+
+// FromStruct create a new Form with one element for each exported field of the struct v.
+// The elements are configured with the "form" tag of each field, e.g.:
+//
+//	City string `form:"type=select,label=City,placeholder=Choose a city,group=col-md-6 mb-2"`
+//
+// Available keys: name, id, type, label, value, placeholder, help, min, max, step, pattern,
+// class, labelclass and group; class, labelclass and group accept several classes separated by spaces.
+// Values with commas are written between single quotes, e.g. `form:"placeholder='Hello, world'"`.
+// Fields tagged with `form:"-"` are skipped.
+func FromStruct(name string, method string, action string, v any) (*Form, error) {
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("goform: FromStruct of nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("goform: FromStruct of non-struct %s", rv.Type())
+	}
+
+	form := Create(name, method, action)
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("form")
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		options, err := parseFormTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("goform: field %s: %w", sf.Name, err)
+		}
+
+		fieldType := options["type"]
+		if fieldType == "" {
+			fieldType = defaultFieldType(sf.Type)
+		}
+
+		fieldName := options["name"]
+		if fieldName == "" {
+			fieldName = sf.Name
+		}
+
+		value, ok := options["value"]
+		if !ok {
+			value = fieldValue(rv.Field(i), fieldType)
+		}
+
+		fieldName, err = form.AddElement(fieldType, fieldName, value)
+		if err != nil {
+			return nil, fmt.Errorf("goform: field %s: %w", sf.Name, err)
+		}
+
+		if multiTypes[fieldType] {
+			form.SetValues(fieldName, fieldValues(rv.Field(i)))
+		}
+
+		if id := options["id"]; id != "" {
+			form.SetID(fieldName, id)
+		}
+		if label := options["label"]; label != "" {
+			form.SetLabel(fieldName, label)
+		}
+		if placeholder := options["placeholder"]; placeholder != "" {
+			form.SetPlaceHolder(fieldName, placeholder)
+		}
+		if help := options["help"]; help != "" {
+			form.SetHelpText(fieldName, help)
+		}
+		for _, class := range strings.Fields(options["class"]) {
+			form.AddClass(fieldName, class)
+		}
+		for _, class := range strings.Fields(options["labelclass"]) {
+			form.AddLabelClass(fieldName, class)
+		}
+		if min := options["min"]; min != "" {
+			form.SetMin(fieldName, min)
+		}
+		if max := options["max"]; max != "" {
+			form.SetMax(fieldName, max)
+		}
+		if step := options["step"]; step != "" {
+			form.SetStep(fieldName, step)
+		}
+		if pattern := options["pattern"]; pattern != "" {
+			form.SetPattern(fieldName, pattern)
+		}
+		for _, class := range strings.Fields(options["group"]) {
+			form.AddGroupClass(fieldName, class)
+		}
+		if fieldType == "checkbox" && rv.Field(i).Kind() == reflect.Bool {
+			form.SetChecked(fieldName, rv.Field(i).Bool())
+		}
+	}
+
+	return form, nil
+}
+
+// tagKeys allowed keys of the form tag
+var tagKeys = map[string]bool{
+	"name":        true,
+	"id":          true,
+	"type":        true,
+	"label":       true,
+	"value":       true,
+	"placeholder": true,
+	"help":        true,
+	"class":       true,
+	"labelclass":  true,
+	"group":       true,
+	"min":         true,
+	"max":         true,
+	"step":        true,
+	"pattern":     true,
+}
+
+// parseFormTag split the tag in key=value pairs separated by commas,
+// values with commas are written between single quotes: label='Hello, world'
+func parseFormTag(tag string) (map[string]string, error) {
+
+	options := map[string]string{}
+
+	for tag != "" {
+
+		key, rest, ok := strings.Cut(tag, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !tagKeys[key] {
+			item, _, _ := strings.Cut(tag, ",")
+			return nil, fmt.Errorf("invalid tag option %q, values with commas must be quoted: key='a, b'", item)
+		}
+
+		value := ""
+		if strings.HasPrefix(rest, "'") {
+			end := strings.Index(rest[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("tag option %q without closing quote", key)
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+			if rest != "" && rest[0] != ',' {
+				return nil, fmt.Errorf("tag option %q with text after the closing quote", key)
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		options[key] = value
+		tag = rest
+	}
+
+	return options, nil
+}
+
+// defaultFieldType input type used when the tag do not set one
+func defaultFieldType(t reflect.Type) string {
+
+	if t == fileHeaderType || (t.Kind() == reflect.Slice && t.Elem() == fileHeaderType) {
+		return "file"
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "date"
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return "multiselect"
+		}
+	case reflect.Bool:
+		return "checkbox"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+
+	return "text"
+}
+
+// fieldValue initial value of the element, empty for zero values and for the
+// inputs without value (checkbox, file and multi-valued inputs)
+func fieldValue(v reflect.Value, fieldType string) string {
+
+	if v.IsZero() || fieldType == "file" || multiTypes[fieldType] {
+		return ""
+	}
+	if v.Kind() == reflect.Bool {
+		return ""
+	}
+	if v.Kind() == reflect.Pointer {
+		return fieldValue(v.Elem(), fieldType)
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return formatTime(t, fieldType)
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return string(v.Bytes())
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// fieldValues initial selected values of a multi-valued element, one for each item of the slice
+func fieldValues(v reflect.Value) []string {
+
+	values := []string{}
+	if v.Kind() != reflect.Slice {
+		if value := fieldValue(v, ""); value != "" {
+			values = append(values, value)
+		}
+		return values
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		values = append(values, fieldValue(v.Index(i), ""))
+	}
+
+	return values
+}
+
+// formatTime format the time with the layout of the input type, RFC 3339 for other types
+func formatTime(t time.Time, fieldType string) string {
+
+	switch fieldType {
+	case "date":
+		return t.Format("2006-01-02")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04")
+	case "time":
+		return t.Format("15:04")
+	case "month":
+		return t.Format("2006-01")
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,163 @@
+package goform
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromStructDefaultTypes(t *testing.T) {
+
+	var data struct {
+		Name    string
+		Age     int
+		Price   *float64
+		Active  bool
+		Born    time.Time
+		Tags    []string
+		Raw     []byte
+		Avatar  *multipart.FileHeader
+		Gallery []*multipart.FileHeader
+	}
+
+	data.Tags = []string{"go", "html"}
+
+	form, err := FromStruct("profile", "POST", "/", &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"name":    "text",
+		"age":     "number",
+		"price":   "number",
+		"active":  "checkbox",
+		"born":    "date",
+		"tags":    "multiselect",
+		"raw":     "text",
+		"avatar":  "file",
+		"gallery": "file",
+	}
+	for name, fieldType := range want {
+		if got := form.Elements[name].FieldType; got != fieldType {
+			t.Errorf("%s: type %q, want %q", name, got, fieldType)
+		}
+	}
+
+	if got := form.Values("tags"); !reflect.DeepEqual(got, []string{"go", "html"}) {
+		t.Errorf("tags: values %v", got)
+	}
+	if got := form.Elements["tags"].Value; got != "" {
+		t.Errorf("tags: value %q, want empty", got)
+	}
+}
+
+func TestFromStructValues(t *testing.T) {
+
+	at := time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC)
+	data := struct {
+		Name   string
+		Age    int
+		Active bool
+		Born   time.Time
+		Avatar *multipart.FileHeader
+		Note   string `form:"value=Fixed"`
+	}{"Jane", 42, true, at, &multipart.FileHeader{Filename: "me.png"}, "ignored"}
+
+	form, err := FromStruct("profile", "POST", "/", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"name":   "Jane",
+		"age":    "42",
+		"active": "",
+		"born":   "2024-03-09",
+		"avatar": "",
+		"note":   "Fixed",
+	}
+	for name, want := range tests {
+		if got := form.Elements[name].Value; got != want {
+			t.Errorf("%s: value %q, want %q", name, got, want)
+		}
+	}
+	if !form.Elements["active"].Checked {
+		t.Error("active: unchecked")
+	}
+}
+
+func TestFromStructTimeLayouts(t *testing.T) {
+
+	at := time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC)
+	data := struct {
+		Day   time.Time `form:"type=date"`
+		Local time.Time `form:"type=datetime-local"`
+		Hour  time.Time `form:"type=time"`
+		Month time.Time `form:"type=month"`
+		Week  time.Time `form:"type=week"`
+		Text  time.Time `form:"type=text"`
+	}{at, at, at, at, at, at}
+
+	form, err := FromStruct("times", "POST", "/", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"day":   "2024-03-09",
+		"local": "2024-03-09T14:30",
+		"hour":  "14:30",
+		"month": "2024-03",
+		"week":  "2024-W10",
+		"text":  "2024-03-09T14:30:00Z",
+	}
+	for name, want := range tests {
+		if got := form.Elements[name].Value; got != want {
+			t.Errorf("%s: value %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParseFormTag(t *testing.T) {
+
+	tests := []struct {
+		tag     string
+		want    map[string]string
+		invalid bool
+	}{
+		{"", map[string]string{}, false},
+		{"type=select,label=City", map[string]string{"type": "select", "label": "City"}, false},
+		{"label=What's your name", map[string]string{"label": "What's your name"}, false},
+		{"placeholder='Hello, world',help=Hi", map[string]string{"placeholder": "Hello, world", "help": "Hi"}, false},
+		{"label='a=b, c',value=''", map[string]string{"label": "a=b, c", "value": ""}, false},
+		{"label=City,", map[string]string{"label": "City"}, false},
+		{"placeholder=Hello, world", nil, true},
+		{"label='Hello, world", nil, true},
+		{"label='Hello' world", nil, true},
+		{"unknown=x", nil, true},
+		{"label", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFormTag(tt.tag)
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("parseFormTag(%q) = %v, want error", tt.tag, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFormTag(%q) = %v, %v, want %v", tt.tag, got, err, tt.want)
+		}
+	}
+
+	_, err := FromStruct("profile", "POST", "/", struct {
+		Name string `form:"placeholder=Hello, world"`
+	}{})
+	if err == nil || !strings.Contains(err.Error(), "quoted") {
+		t.Errorf("FromStruct error %v, want the quoting hint", err)
+	}
+}
@@ -1,11 +1,91 @@
 package goform
 
 import (
+	"fmt"
 	"html/template"
 	"log"
+	"sync"
 )
 
-var themes = map[string]map[string]string{}
+var (
+	themes      = map[string]map[string]string{}
+	themesMutex sync.RWMutex
+)
+
+// conditionsScript runtime of the conditions of the fields (see ShowIf), hides or disables
+// the elements (or its group) when the conditions in data-goform-conditions do not match
+const conditionsScript = `
+	<script>
+	(function () {
+		var form = document.getElementById({{.ID}});
+		var rules = JSON.parse(form.getAttribute("data-goform-conditions"));
+		function values(name) {
+			var result = [];
+			form.querySelectorAll("[name='" + name + "']").forEach(function (input) {
+				if ((input.type === "checkbox" || input.type === "radio") && !input.checked) {
+					return;
+				}
+				if (input.multiple) {
+					Array.prototype.forEach.call(input.selectedOptions, function (option) { result.push(option.value); });
+					return;
+				}
+				result.push(input.value);
+			});
+			return result;
+		}
+		function match(condition) {
+			var submitted = values(condition.field), list = condition.value.split(",");
+			var found = list.some(function (value) { return submitted.indexOf(value) >= 0; });
+			switch (condition.operator) {
+			case "eq": return submitted.indexOf(condition.value) >= 0;
+			case "ne": return submitted.indexOf(condition.value) < 0;
+			case "in": return found;
+			case "notin": return !found;
+			case "empty": return submitted.join("") === "";
+			case "notempty": return submitted.join("") !== "";
+			}
+			return false;
+		}
+		function update() {
+			Object.keys(rules).forEach(function (name) {
+				// The group of the element or, in themes without groups, every input of its name
+				var group = document.getElementById("group_" + name);
+				var elements = group ? [group] : Array.prototype.slice.call(form.querySelectorAll("[name='" + name + "']"));
+				if (!elements.length) {
+					return;
+				}
+				var show = true, enable = true;
+				rules[name].forEach(function (condition) {
+					if (condition.action === "show") {
+						show = show && match(condition);
+					} else {
+						enable = enable && match(condition);
+					}
+				});
+				elements.forEach(function (element) {
+					element.hidden = !show;
+					var inputs = element.matches("input, select, textarea, button, fieldset") ? [element] : element.querySelectorAll("input, select, textarea, button");
+					Array.prototype.forEach.call(inputs, function (input) { input.disabled = !(show && enable); });
+				});
+			});
+		}
+		form.addEventListener("input", update);
+		form.addEventListener("change", update);
+		update();
+	})();
+	</script>`
+
+// html5Types HTML5 input types sharing the same template
+var html5Types = []string{"email", "number", "tel", "url", "search", "date", "time", "datetime-local", "month", "week", "color", "range"}
+
+// themeCache compiled templates of the themes shared by all the forms, parsed once by theme and input
+var themeCache = &templateCache{templates: make(map[string]*template.Template)}
+
+// templateCache structure, compiled templates safe for concurrent use.
+type templateCache struct {
+	mutex     sync.RWMutex
+	templates map[string]*template.Template
+}
 
 func init() {
 
@@ -15,34 +95,49 @@ func init() {
 
 	// HTML plain inputs
 
-	themes["html"]["form"] = `<form{{if .Name}} name="{{.Name}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} method="{{ .Method }}" action="{{ .Action }}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}: {{$v}}; {{end}}"{{end}}{{ if eq .MultipartFormData "enabled" }} enctype="multipart/form-data"{{end}}{{ if .Classes }} class="{{range .Classes}}{{.}} {{end}}"{{end}}>
-			{{ .RenderElements }}
-	</form>`
+	themes["html"]["form"] = `<form{{if .Name}} name="{{.Name}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} method="{{ .Method }}" action="{{ .Action }}"{{if .CSS}} style="{{.Style}}"{{end}}{{ if eq .MultipartFormData "enabled" }} enctype="multipart/form-data"{{end}}{{ if .Classes }} class="{{range .Classes}}{{.}} {{end}}"{{end}}{{.ConditionsAttr}}>
+			{{ with .CurrentStep }}<p>{{.Title}} ({{.Number}}/{{.Total}})</p>{{end}}
+			{{ .ElementsHTML }}
+	</form>{{ if .HasConditions }}` + conditionsScript + `{{end}}`
 
 	themes["html"]["label"] = `
-	<label{{if .Name}} name="{{.Name}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{.Value}}</label>`
+	<label{{if .Name}} name="{{.Name}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{ if .RichLabel }}{{.LabelContent}}{{else}}{{.Value}}{{end}}</label>`
 
-	themes["html"]["text"] = `<input type="text" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}{{ if .Value}} value="{{.Value}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>`
+	themes["html"]["textlabel"] = `<span id="group_{{.Name}}">{{if .LabelContent}}<label{{if .ID}} for="static_{{.ID}}"{{end}} class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{.LabelContent}}</label> {{end}}<input type="text" readonly{{if .ID}} name="static_{{.ID}}" id="static_{{.ID}}"{{end}} value="{{.Value}}"></span>`
 
-	themes["html"]["password"] = `<input type="password" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>`
+	themes["html"]["text"] = `<input type="text" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{ if .Value}} value="{{.Value}}"{{end}}{{if .Pattern}} pattern="{{.Pattern}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}{{if .Options}} list="{{.ID}}_list"{{end}}>{{if .Options}}<datalist id="{{.ID}}_list">{{range .Options}}<option value="{{.Key}}"{{if .Disabled}} disabled{{end}}>{{.Value}}</option>{{end}}</datalist>{{end}}`
 
-	themes["html"]["select"] = `<select name="{{.Name}}" class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .ID}} id="{{.ID}}"{{end}}{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}: {{$v}}; {{end}}"{{end}}>
+	themes["html"]["password"] = `<input type="password" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>`
+
+	themes["html"]["select"] = `<select name="{{.Name}}" class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .ID}} id="{{.ID}}"{{end}}{{if .CSS}} style="{{.Style}}"{{end}}>
 	{{ $p := . }}
-	{{range $option := .Options}}
-	<option value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} selected{{end}}>{{$option.Value}}</option>
-	{{end}}
+	{{range $group := .OptionGroups}}{{if $group.Label}}<optgroup label="{{$group.Label}}">{{end}}{{range $option := $group.Options}}
+	<option value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} selected{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}>{{$option.Value}}</option>
+	{{end}}{{if $group.Label}}</optgroup>{{end}}{{end}}
 	</select>`
 
-	themes["html"]["radio"] = `{{ $p := . }}
-	{{range $option := .Options}}
-	<input type="radio" value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} selected{{end}}> {{$option.Value}}<br />
-	{{end}}`
+	themes["html"]["radio"] = `<span id="group_{{.Name}}">{{ $p := . }}
+	{{range $group := .OptionGroups}}{{if $group.Label}}<strong>{{$group.Label}}</strong><br />{{end}}{{range $option := $group.Options}}
+	<input type="radio" name="{{$p.Name}}" value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} checked{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}> {{$option.Value}}<br />
+	{{end}}{{end}}</span>`
 
-	themes["html"]["textarea"] = `<textarea name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}: {{$v}}; {{end}}"{{end}}{{if .PlaceHolder}} id="{{.PlaceHolder}}"{{end}} rows="6">{{.Value}}</textarea>`
+	themes["html"]["multiselect"] = `<select multiple name="{{.Name}}" class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .ID}} id="{{.ID}}"{{end}}{{if .CSS}} style="{{.Style}}"{{end}}>
+	{{ $p := . }}
+	{{range $group := .OptionGroups}}{{if $group.Label}}<optgroup label="{{$group.Label}}">{{end}}{{range $option := $group.Options}}
+	<option value="{{$option.Key}}"{{ if $p.HasValue $option.Key}} selected{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}>{{$option.Value}}</option>
+	{{end}}{{if $group.Label}}</optgroup>{{end}}{{end}}
+	</select>`
+
+	themes["html"]["checkboxgroup"] = `<span id="group_{{.Name}}">{{ $p := . }}
+	{{range $group := .OptionGroups}}{{if $group.Label}}<strong>{{$group.Label}}</strong><br />{{end}}{{range $option := $group.Options}}
+	<input type="checkbox" name="{{$p.Name}}" value="{{$option.Key}}"{{ if $p.HasValue $option.Key}} checked{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}> {{$option.Value}}<br />
+	{{end}}{{end}}</span>`
 
-	themes["html"]["checkbox"] = `<input type="checkbox" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} value="{{.Value}}" class="{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}>`
+	themes["html"]["textarea"] = `<textarea name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .PlaceHolder}} id="{{.PlaceHolder}}"{{end}} rows="6">{{.Value}}</textarea>`
 
-	themes["html"]["file"] = `<input type="file" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>`
+	themes["html"]["checkbox"] = `<input type="checkbox" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} value="{{.Value}}" class="{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .Checked}} checked{{end}}>`
+
+	themes["html"]["file"] = `<input type="file" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>`
 
 	themes["html"]["hidden"] = `<input type="hidden" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} {{ if .Value}} value="{{.Value}}"{{end}}>`
 
@@ -52,95 +147,173 @@ func init() {
 
 	themes["html"]["row"] = `<br />`
 
+	themes["html"]["repeater"] = `{{define "row"}}<div class="goform-row">{{.}} <button type="button" class="goform-remove">Remove</button></div>{{end}}
+	<div id="group_{{.Name}}">
+	{{ if .LabelContent }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{.LabelContent}}</label>{{end}}
+	<div class="goform-rows">{{range .RowsHTML}}{{template "row" .}}{{end}}</div>
+	<template>{{template "row" .RowTemplate}}</template>
+	<button type="button" class="goform-add{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}">{{if .Value}}{{.Value}}{{else}}Add another{{end}}</button>
+	<script>
+	(function () {
+		var group = document.getElementById("group_{{.Name}}");
+		var rows = group.querySelector(".goform-rows");
+		var template = group.querySelector("template");
+		var add = group.querySelector(".goform-add");
+		var min = {{.Repeater.Min}}, max = {{.Repeater.Max}}, next = rows.children.length;
+		function update() {
+			var count = rows.children.length;
+			add.disabled = max > 0 && count >= max;
+			rows.querySelectorAll(".goform-remove").forEach(function (button) { button.disabled = count <= min; });
+		}
+		add.addEventListener("click", function () {
+			rows.insertAdjacentHTML("beforeend", template.innerHTML.split("__index__").join(next++));
+			update();
+		});
+		rows.addEventListener("click", function (event) {
+			var button = event.target.closest(".goform-remove");
+			if (button) {
+				button.closest(".goform-row").remove();
+				update();
+			}
+		});
+		update();
+	})();
+	</script>
+	</div>`
+
+	themes["html"]["fieldset"] = `<fieldset name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}}{{ if .Classes }} class="{{range .Classes}}{{.}} {{end}}"{{end}}{{if .CSS}} style="{{.Style}}"{{end}}{{if .Disabled}} disabled{{end}}>
+	{{ if .LabelContent }}<legend{{ if .LabelClass }} class="{{range .LabelClass}}{{.}} {{end}}"{{end}}>{{.LabelContent}}</legend>{{end}}
+	{{.Content}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help">{{.HelpContent}}</small>{{end}}
+	</fieldset>`
+
+	// HTML5 inputs, the type attribute is the field type
+	for _, inputType := range html5Types {
+		themes["html"][inputType] = `<input type="{{.FieldType}}" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{ if .Value}} value="{{.Value}}"{{end}}{{if .Min}} min="{{.Min}}"{{end}}{{if .Max}} max="{{.Max}}"{{end}}{{if .Step}} step="{{.Step}}"{{end}}{{if .Pattern}} pattern="{{.Pattern}}"{{end}}{{.ParamsAttr}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}{{if .Options}} list="{{.ID}}_list"{{end}}>{{if .Options}}<datalist id="{{.ID}}_list">{{range .Options}}<option value="{{.Key}}"{{if .Disabled}} disabled{{end}}>{{.Value}}</option>{{end}}</datalist>{{end}}`
+	}
+
 	// Bootstrap5 inputs
 
 	themes["bootstrap5"]["form"] = `
-	<form{{if .Name}} name="{{.Name}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} method="{{ .Method }}" action="{{ .Action }}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}: {{$v}}; {{end}}"{{end}}{{ if eq .MultipartFormData "enabled" }} enctype="multipart/form-data"{{end}}{{ if .Classes }} class="{{range .Classes}} {{.}}{{end}}"{{end}}>
+	<form{{if .Name}} name="{{.Name}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} method="{{ .Method }}" action="{{ .Action }}"{{if .CSS}} style="{{.Style}}"{{end}}{{ if eq .MultipartFormData "enabled" }} enctype="multipart/form-data"{{end}}{{ if .Classes }} class="{{range .Classes}} {{.}}{{end}}"{{end}}{{.ConditionsAttr}}>
+	{{ with .CurrentStep }}
+	<div class="mb-3">
+	<div class="d-flex justify-content-between"><strong>{{.Title}}</strong><small class="text-muted">{{.Number}} / {{.Total}}</small></div>
+	<div class="progress" role="progressbar" aria-valuenow="{{.Percent}}" aria-valuemin="0" aria-valuemax="100"><div class="progress-bar" style="width: {{.Percent}}%"></div></div>
+	</div>
+	{{end}}
 	<div class="row" name="row_main" id="row_main">
-		{{ .RenderElements }}
+		{{ .ElementsHTML }}
 	</div>
-	</form>`
+	</form>{{ if .HasConditions }}` + conditionsScript + `{{end}}`
 
 	themes["bootstrap5"]["label"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	<label{{if .ID}} name="{{.ID}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} class="control-label{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}>{{.Value}}</label>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	<label{{if .ID}} name="{{.ID}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} class="control-label{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}>{{ if .RichLabel }}{{.LabelContent}}{{else}}{{.Value}}{{end}}</label>
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["textlabel"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}row">
-	<label{{if .ID}} name="{{.ID}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} class="col-sm-2 col-form-label{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}>{{.Label}}</label>
+	<label{{if .ID}} name="{{.ID}}"{{end}}{{if .ID}} id="{{.ID}}"{{end}} class="col-sm-2 col-form-label{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}>{{.LabelContent}}</label>
 	<label class="col-sm-10">
 		<input type="text" readonly class="form-control-plaintext" {{if .ID}} name="static_{{.ID}}"{{end}}{{if .ID}} id="static_{{.ID}}"{{end}} value="{{.Value}}">
 	</label>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["text"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{ if .Label }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
-	<input type="text" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}{{ if .Value}} value="{{.Value}}"{{end}}{{if .Params}}{{range $k, $v := .Params}} {{$k}}="{{$v}}"{{end}}{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .LabelContent }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
+	<input type="text" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{ if .Value}} value="{{.Value}}"{{end}}{{if .Pattern}} pattern="{{.Pattern}}"{{end}}{{.ParamsAttr}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}{{if .Options}} list="{{.ID}}_list"{{end}}>{{if .Options}}<datalist id="{{.ID}}_list">{{range .Options}}<option value="{{.Key}}"{{if .Disabled}} disabled{{end}}>{{.Value}}</option>{{end}}</datalist>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["password"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{ if .Label }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
-	<input type="password" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .LabelContent }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
+	<input type="password" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["select"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{if .Label}}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
-	<select name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}: {{$v}}; {{end}}"{{end}}>
-	{{ $p := . }}{{range $option := .Options}}
-	<option value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} selected{{end}}>{{$option.Value}}</option>{{end}}
+	{{if .LabelContent}}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
+	<select name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}>
+	{{ $p := . }}{{range $group := .OptionGroups}}{{if $group.Label}}<optgroup label="{{$group.Label}}">{{end}}{{range $option := $group.Options}}
+	<option value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} selected{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}>{{$option.Value}}</option>{{end}}{{if $group.Label}}</optgroup>{{end}}{{end}}
 	</select>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["radio"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{if .Label}}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
+	{{if .LabelContent}}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
 	{{ $p := . }}
-	{{range $option := .Options}}
+	{{range $group := .OptionGroups}}{{if $group.Label}}
+	<div class="form-label fw-bold">{{$group.Label}}</div>{{end}}{{range $option := $group.Options}}
 	<div class="form-check">
-	<input class="form-check-input" type="radio" name="{{$p.Name}}" id="{{$p.ID}}_{{$option.Key}}" value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} checked{{end}}>
+	<input class="form-check-input" type="radio" name="{{$p.Name}}" id="{{$p.ID}}_{{$option.Key}}" value="{{$option.Key}}"{{ if eq $p.Value $option.Key}} checked{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}>
 	<label class="form-check-label" for="{{$p.ID}}_{{$option.Key}}">
 	{{$option.Value}}
 	</label>
 	</div>
-	{{end}}
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{end}}{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
+	</div>`
+
+	themes["bootstrap5"]["multiselect"] = `
+	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
+	{{if .LabelContent}}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
+	<select multiple name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-select{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}>
+	{{ $p := . }}{{range $group := .OptionGroups}}{{if $group.Label}}<optgroup label="{{$group.Label}}">{{end}}{{range $option := $group.Options}}
+	<option value="{{$option.Key}}"{{ if $p.HasValue $option.Key}} selected{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}>{{$option.Value}}</option>{{end}}{{if $group.Label}}</optgroup>{{end}}{{end}}
+	</select>
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
+	</div>`
+
+	themes["bootstrap5"]["checkboxgroup"] = `
+	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
+	{{if .LabelContent}}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{.LabelContent}}</label>{{end}}
+	{{ $p := . }}
+	{{range $group := .OptionGroups}}{{if $group.Label}}
+	<div class="form-label fw-bold">{{$group.Label}}</div>{{end}}{{range $option := $group.Options}}
+	<div class="form-check">
+	<input class="form-check-input" type="checkbox" name="{{$p.Name}}" id="{{$p.ID}}_{{$option.Key}}" value="{{$option.Key}}"{{ if $p.HasValue $option.Key}} checked{{end}}{{if $option.Disabled}} disabled{{end}}{{$option.Attrs}}>
+	<label class="form-check-label" for="{{$p.ID}}_{{$option.Key}}">
+	{{$option.Value}}
+	</label>
+	</div>
+	{{end}}{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["textarea"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{ if .Label }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
-	<textarea name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }} {{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}: {{$v}}; {{end}}"{{end}}{{if .PlaceHolder}} id="{{.PlaceHolder}}"{{end}} rows="6">{{.Value}}</textarea>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .LabelContent }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
+	<textarea name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="form-control{{ if .Classes }} {{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .PlaceHolder}} id="{{.PlaceHolder}}"{{end}} rows="6">{{.Value}}</textarea>
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["checkbox"] = `
 	<div id="group_{{.Name}}" class="form-check{{ if .GroupClass }}{{range .GroupClass}} {{.}}{{end}}{{end}}">
-	<input type="checkbox" name="{{.Name}}"{{ if .ID }}{{if .ID}} id="{{.ID}}"{{end}} value="{{.Value}}" class="{{range .Classes}} {{.}}{{end}}"{{end}}{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}>
-	{{ if .Label }}
+	<input type="checkbox" name="{{.Name}}"{{ if .ID }}{{if .ID}} id="{{.ID}}"{{end}} value="{{.Value}}" class="{{range .Classes}} {{.}}{{end}}"{{end}}{{if .CSS}} style="{{.Style}}"{{end}}{{if .Checked}} checked{{end}}>
+	{{ if .LabelContent }}
 	<label class="form-check-label" for="{{.Name}}">
-	{{.Label}}
+	{{.LabelContent}}
 	</label>
 	{{end}}
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["file"] = `
 	<div id="group_{{.Name}}" name="group_{{.Name}}"{{if .ID}} id="group_{{.ID}}"{{end}} class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
 	{{ if .Value }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Value}}</label>{{end}}
 	<div class="custom-file">
-	<input type="file" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{range $k, $v := .CSS}}{{$k}}:{{$v}};{{end}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>
-	{{ if .Label }}<label class="custom-file-label" for="{{.Name}}">{{.Label}}</label>{{end}}
+	<input type="file" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if .Classes }}{{range .Classes}}{{.}} {{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}>
+	{{ if .LabelContent }}<label class="custom-file-label" for="{{.Name}}">{{.LabelContent}}</label>{{end}}
 	</div>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["hidden"] = `
@@ -148,31 +321,194 @@ func init() {
 
 	themes["bootstrap5"]["button"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{ if .Label }}<label class="control-label {{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
+	{{ if .LabelContent }}<label class="control-label {{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
 	<button type="button" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="btn{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}">{{.Value}}</button>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["submit"] = `
 	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
-	{{ if .Label }}<label class="control-label {{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.Label}}</label>{{end}}
+	{{ if .LabelContent }}<label class="control-label {{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
 	<button type="submit" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="btn{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}">{{.Value}}</button>
-	{{ if .HelpText }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpText}}</small>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
 	</div>`
 
 	themes["bootstrap5"]["row"] = `
 	</div>
 	<div class="row" name="row_{{.Name}}"{{if .ID}} id="row_{{.ID}}"{{end}}>`
 
+	themes["bootstrap5"]["repeater"] = `{{define "row"}}<div class="row goform-row">{{.}}<div class="col-auto"><button type="button" class="btn btn-outline-danger goform-remove">&times;</button></div></div>{{end}}
+	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
+	{{ if .LabelContent }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{.LabelContent}}</label>{{end}}
+	<div class="goform-rows">{{range .RowsHTML}}{{template "row" .}}{{end}}</div>
+	<template>{{template "row" .RowTemplate}}</template>
+	<button type="button" class="btn{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{else}} btn-secondary{{end}} goform-add">{{if .Value}}{{.Value}}{{else}}Add another{{end}}</button>
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
+	<script>
+	(function () {
+		var group = document.getElementById("group_{{.Name}}");
+		var rows = group.querySelector(".goform-rows");
+		var template = group.querySelector("template");
+		var add = group.querySelector(".goform-add");
+		var min = {{.Repeater.Min}}, max = {{.Repeater.Max}}, next = rows.children.length;
+		function update() {
+			var count = rows.children.length;
+			add.disabled = max > 0 && count >= max;
+			rows.querySelectorAll(".goform-remove").forEach(function (button) { button.disabled = count <= min; });
+		}
+		add.addEventListener("click", function () {
+			rows.insertAdjacentHTML("beforeend", template.innerHTML.split("__index__").join(next++));
+			update();
+		});
+		rows.addEventListener("click", function (event) {
+			var button = event.target.closest(".goform-remove");
+			if (button) {
+				button.closest(".goform-row").remove();
+				update();
+			}
+		});
+		update();
+	})();
+	</script>
+	</div>`
+
+	themes["bootstrap5"]["fieldset"] = `
+	<fieldset id="group_{{.Name}}" name="{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}"{{if .Disabled}} disabled{{end}}>
+	<div{{if .ID}} id="{{.ID}}"{{end}} class="card{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}>
+	<div class="card-body">
+	{{ if .LabelContent }}<legend class="card-title{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}">{{.LabelContent}}</legend>{{end}}
+	<div class="row" name="row_{{.Name}}">
+	{{.Content}}
+	</div>
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
+	</div>
+	</div>
+	</fieldset>`
+
+	// HTML5 inputs, the type attribute is the field type
+	for _, inputType := range html5Types {
+		themes["bootstrap5"][inputType] = `
+	<div id="group_{{.Name}}" class="{{ if .GroupClass }}{{range .GroupClass}}{{.}} {{end}}{{end}}">
+	{{ if .LabelContent }}<label class="control-label{{ if .LabelClass }}{{range .LabelClass}} {{.}}{{end}}{{end}}"{{if .ID}} for="{{.ID}}"{{end}}>{{.LabelContent}}</label>{{end}}
+	<input type="{{.FieldType}}" name="{{.Name}}"{{if .ID}} id="{{.ID}}"{{end}} class="{{ if eq .FieldType "range" }}form-range{{else}}form-control{{ if eq .FieldType "color" }} form-control-color{{end}}{{end}}{{ if .Classes }}{{range .Classes}} {{.}}{{end}}{{end}}"{{if .CSS}} style="{{.Style}}"{{end}}{{ if .Value}} value="{{.Value}}"{{end}}{{if .Min}} min="{{.Min}}"{{end}}{{if .Max}} max="{{.Max}}"{{end}}{{if .Step}} step="{{.Step}}"{{end}}{{if .Pattern}} pattern="{{.Pattern}}"{{end}}{{.ParamsAttr}}{{if .PlaceHolder}} placeholder="{{.PlaceHolder}}"{{end}}{{if .Options}} list="{{.ID}}_list"{{end}}>{{if .Options}}<datalist id="{{.ID}}_list">{{range .Options}}<option value="{{.Key}}"{{if .Disabled}} disabled{{end}}>{{.Value}}</option>{{end}}</datalist>{{end}}
+	{{ if .HelpContent }}<small id="{{.Name}}Help" class="form-text text-muted">{{.HelpContent}}</small>{{end}}
+	</div>`
+	}
+
 }
 
-// HTMLTemplate parse html template
+// HTMLTemplate parse html template, errors are logged.
 func HTMLTemplate(theme string, input string) *template.Template {
 
-	t, err := template.New("tmpl").Parse(themes[theme][input])
+	t, err := parseTheme(theme, input)
 	if err != nil {
 		log.Println(err)
 	}
 
 	return t
 }
+
+// parseTheme parse the template of the input in the theme
+func parseTheme(theme string, input string) (*template.Template, error) {
+
+	themesMutex.RLock()
+	text, ok := themes[theme][input]
+	themesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("goform: theme %q has no template for %q", theme, input)
+	}
+
+	return template.New("tmpl").Parse(text)
+}
+
+// themeTemplate returns the compiled template of the input in the theme, parsed only the first time
+func themeTemplate(theme string, input string) (*template.Template, error) {
+	return themeCache.get(theme+"/"+input, func() (*template.Template, error) {
+		return parseTheme(theme, input)
+	})
+}
+
+// get returns the template of the cache or parse and store it
+func (c *templateCache) get(key string, parse func() (*template.Template, error)) (*template.Template, error) {
+
+	c.mutex.RLock()
+	t, ok := c.templates[key]
+	c.mutex.RUnlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	// Another goroutine could store it first, keep only one template
+	if cached, ok := c.templates[key]; ok {
+		t = cached
+	} else {
+		c.templates[key] = t
+	}
+	c.mutex.Unlock()
+
+	return t, nil
+}
+
+// RegisterTheme register a new theme, templates must have the "form" template and one
+// template for each field type, every template is parsed to check it.
+func RegisterTheme(name string, templates map[string]string) error {
+
+	for _, input := range fieldTypes {
+		if _, ok := templates[input]; !ok {
+			return fmt.Errorf("goform: theme %q has no template for %q", name, input)
+		}
+	}
+	if _, ok := templates["form"]; !ok {
+		return fmt.Errorf("goform: theme %q has no template for %q", name, "form")
+	}
+
+	theme := make(map[string]string, len(templates))
+	for input, text := range templates {
+		if _, ok := fieldTypes[input]; !ok && input != "form" {
+			return fmt.Errorf("goform: theme %q has a template for unknown type %q", name, input)
+		}
+		if _, err := template.New("tmpl").Parse(text); err != nil {
+			return fmt.Errorf("goform: theme %q, template %q: %w", name, input, err)
+		}
+		theme[input] = text
+	}
+
+	themesMutex.Lock()
+	defer themesMutex.Unlock()
+
+	if _, ok := themes[name]; ok {
+		return fmt.Errorf("goform: theme %q already exists", name)
+	}
+	themes[name] = theme
+
+	return nil
+}
+
+// ExtendTheme register a new theme with the templates of the base theme,
+// replacing only the templates in overrides.
+func ExtendTheme(base string, name string, overrides map[string]string) error {
+
+	themesMutex.RLock()
+	baseTheme, ok := themes[base]
+	templates := make(map[string]string, len(baseTheme))
+	for input, text := range baseTheme {
+		templates[input] = text
+	}
+	themesMutex.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("goform: theme %q do not exists", base)
+	}
+
+	for input, text := range overrides {
+		templates[input] = text
+	}
+
+	return RegisterTheme(name, templates)
+}
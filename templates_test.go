@@ -0,0 +1,311 @@
+package goform
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+// benchmarkForm returns a form with 50 elements of several types
+func benchmarkForm() *Form {
+
+	form := Create("large", "POST", "/")
+	types := []string{"text", "password", "select", "textarea", "checkbox", "radio", "hidden", "file"}
+	options := []OptionItem{{Key: "a", Value: "Option A"}, {Key: "b", Value: "Option B"}, {Key: "c", Value: "Option C"}}
+
+	for i := 0; i < 50; i++ {
+		fieldType := types[i%len(types)]
+		name := form.NewElement(fieldType, fmt.Sprintf("field%d", i), "value")
+		form.SetLabel(name, fmt.Sprintf("Field %d", i))
+		form.SetHelpText(name, "Help text")
+		if fieldType == "select" || fieldType == "radio" {
+			form.SetOptions(name, options)
+		}
+	}
+
+	return form
+}
+
+// renderElementsUncached render the elements parsing every template, as before the cache
+func renderElementsUncached(form *Form) (string, error) {
+
+	formTemplates := map[string]*template.Template{}
+	for input := range form.FormTypes {
+		tmpl, err := parseTheme(form.TemplateStyle, input)
+		if err != nil {
+			return "", err
+		}
+		formTemplates[input] = tmpl
+	}
+
+	buf := new(bytes.Buffer)
+	fields := form.SortElements()
+	if err := form.renderFields(buf, formTemplates, map[string][]Field{"": fields}, fields); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func TestRenderElementsCachedMatchesUncached(t *testing.T) {
+
+	form := benchmarkForm()
+
+	cached, err := form.ElementsHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncached, err := renderElementsUncached(form)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(cached) != uncached {
+		t.Error("cached and uncached renders differ")
+	}
+}
+
+func BenchmarkRenderElements(b *testing.B) {
+
+	form := benchmarkForm()
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := renderElementsUncached(form); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := form.ElementsHTML(); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkTemplateLookup(b *testing.B) {
+
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := parseTheme("bootstrap5", "select"); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := themeTemplate("bootstrap5", "select"); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkThemeFSLookup(b *testing.B) {
+
+	fsys := fstest.MapFS{"theme/select.html": {Data: []byte(themes["bootstrap5"]["select"])}}
+
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := template.ParseFS(fsys, "theme/select.html"); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		theme := newFSTheme(fsys, "theme")
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := theme.template("select"); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkRender(b *testing.B) {
+
+	form := benchmarkForm()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := form.RenderString(); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// testThemes counter of the themes registered by the tests, the names are unique with -count
+var testThemes int32
+
+// testThemeName returns a theme name not registered yet
+func testThemeName(prefix string) string {
+	return fmt.Sprintf("%s_%d", prefix, atomic.AddInt32(&testThemes, 1))
+}
+
+// copyTheme returns a copy of the templates of the theme
+func copyTheme(theme string) map[string]string {
+
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+
+	templates := make(map[string]string, len(themes[theme]))
+	for input, text := range themes[theme] {
+		templates[input] = text
+	}
+
+	return templates
+}
+
+func TestRegisterTheme(t *testing.T) {
+
+	name := testThemeName("registered")
+	templates := copyTheme("html")
+	templates["text"] = `<input type="text" name="{{.Name}}" data-theme="registered">`
+
+	if err := RegisterTheme(name, templates); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTheme(name, templates); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("duplicate name: error %v", err)
+	}
+	if err := RegisterTheme("html", copyTheme("html")); err == nil {
+		t.Error("built-in theme replaced")
+	}
+
+	// The registered templates are copied
+	templates["text"] = "changed"
+
+	form := Create("contact", "POST", "/contact")
+	form.SetTemplateStyle(name)
+	form.NewElement("text", "name", "")
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, `<input type="text" name="name" data-theme="registered">`) {
+		t.Errorf("render without the registered template:\n%s", html)
+	}
+}
+
+func TestRegisterThemeErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		change func(templates map[string]string)
+		want   string
+	}{
+		{"missing type", func(templates map[string]string) { delete(templates, "select") }, `no template for "select"`},
+		{"missing form", func(templates map[string]string) { delete(templates, "form") }, `no template for "form"`},
+		{"unknown type", func(templates map[string]string) { templates["slider"] = "<input>" }, `unknown type "slider"`},
+		{"parse error", func(templates map[string]string) { templates["text"] = "{{if .Name}}" }, `template "text"`},
+	}
+
+	for _, tt := range tests {
+
+		name := testThemeName("invalid")
+		templates := copyTheme("html")
+		tt.change(templates)
+
+		err := RegisterTheme(name, templates)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+
+		// The invalid theme is not registered
+		if err := RegisterTheme(name, copyTheme("html")); err != nil {
+			t.Errorf("%s: name taken by the invalid theme: %v", tt.name, err)
+		}
+	}
+}
+
+func TestExtendTheme(t *testing.T) {
+
+	name := testThemeName("extended")
+	err := ExtendTheme("bootstrap5", name, map[string]string{
+		"text": `<input type="text" name="{{.Name}}" value="{{.Value}}" data-theme="extended">`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := Create("contact", "POST", "/contact")
+	form.SetTemplateStyle(name)
+	form.NewElement("text", "name", "Jane")
+	form.NewElement("textarea", "message", "Hi")
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(html, `<input type="text" name="name" value="Jane" data-theme="extended">`) {
+		t.Errorf("render without the override:\n%s", html)
+	}
+
+	// The other templates are the ones of the base theme
+	form.SetTemplateStyle("bootstrap5")
+	base, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(base, "Hi</textarea>") || !strings.Contains(html, "Hi</textarea>") || !strings.Contains(html, `class="form-control`) {
+		t.Errorf("render without the base templates:\n%s", html)
+	}
+
+	tests := []struct {
+		name      string
+		base      string
+		overrides map[string]string
+		want      string
+	}{
+		{"missing base", "material", nil, `"material" do not exists`},
+		{"duplicate name", "html", nil, "already exists"},
+		{"unknown type", "html", map[string]string{"slider": "<input>"}, `unknown type "slider"`},
+		{"parse error", "html", map[string]string{"text": "{{end}}"}, `template "text"`},
+	}
+
+	for _, tt := range tests {
+		themeName := testThemeName("extended")
+		if tt.name == "duplicate name" {
+			themeName = name
+		}
+		if err := ExtendTheme(tt.base, themeName, tt.overrides); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
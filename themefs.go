@@ -0,0 +1,116 @@
+package goform
+
+import (
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+)
+
+// fsTheme theme loaded from a file system, the form template is the file <dir>.html
+// and the element templates are the files <dir>/<type>.html
+type fsTheme struct {
+	fsys  fs.FS
+	dir   string
+	cache templateCache
+}
+
+var (
+	fsThemes      = map[string]*fsTheme{}
+	ownThemes     = map[string]*fsTheme{}
+	fsThemesMutex sync.RWMutex
+)
+
+// ownTemplatesFS file system of the templates folder used by SetOwnTemplateStyle,
+// relative to the working directory
+var ownTemplatesFS = os.DirFS("templates")
+
+// newFSTheme create the theme of the dir folder in fsys
+func newFSTheme(fsys fs.FS, dir string) *fsTheme {
+	return &fsTheme{
+		fsys:  fsys,
+		dir:   path.Clean(dir),
+		cache: templateCache{templates: make(map[string]*template.Template)},
+	}
+}
+
+// RegisterThemeFS register the theme name loaded from fsys, the form template is the
+// file <name>.html and the element templates are the files <name>/<type>.html.
+// Forms use it with SetTemplateStyle(name), e.g. with embedded files:
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	sub, _ := fs.Sub(templates, "templates")
+//	goform.RegisterThemeFS("custom_templates", sub)
+func RegisterThemeFS(name string, fsys fs.FS) {
+
+	fsThemesMutex.Lock()
+	fsThemes[name] = newFSTheme(fsys, name)
+	fsThemesMutex.Unlock()
+}
+
+// registeredThemeFS returns the theme registered with RegisterThemeFS
+func registeredThemeFS(name string) *fsTheme {
+
+	fsThemesMutex.RLock()
+	defer fsThemesMutex.RUnlock()
+
+	return fsThemes[name]
+}
+
+// UseThemeFS set the templates of the form from the dir folder in fsys, the form template
+// is the file <dir>.html and the element templates are the files <dir>/<type>.html
+func (f *Form) UseThemeFS(fsys fs.FS, dir string) {
+
+	f.TemplateStyle = dir
+	f.TemplateSource = "FS"
+	f.themeFS = newFSTheme(fsys, dir)
+}
+
+// ownTheme returns the theme of the style in the templates folder, shared by all the forms
+func ownTheme(style string) *fsTheme {
+
+	fsThemesMutex.Lock()
+	defer fsThemesMutex.Unlock()
+
+	theme, ok := ownThemes[style]
+	if !ok {
+		theme = newFSTheme(ownTemplatesFS, style)
+		ownThemes[style] = theme
+	}
+
+	return theme
+}
+
+// template returns the compiled template of the input, parsed only the first time
+func (t *fsTheme) template(input string) (*template.Template, error) {
+	return t.cache.get(input, func() (*template.Template, error) {
+
+		filename := path.Join(t.dir, input+".html")
+		if input == "form" {
+			filename = t.dir + ".html"
+		}
+
+		return template.ParseFS(t.fsys, filename)
+	})
+}
+
+// template returns the compiled template of the input for the form style
+func (f *Form) template(input string) (*template.Template, error) {
+
+	// Some field types share the template of other type
+	if name, ok := fieldTypes[input]; ok {
+		input = name
+	}
+
+	if f.themeFS != nil {
+		return f.themeFS.template(input)
+	}
+	if theme := registeredThemeFS(f.TemplateStyle); theme != nil {
+		return theme.template(input)
+	}
+
+	return themeTemplate(f.TemplateStyle, input)
+}
@@ -0,0 +1,128 @@
+package goform
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// mapTheme returns a theme with the form and text templates in the dir folder
+func mapTheme(dir string) fstest.MapFS {
+	return fstest.MapFS{
+		dir + ".html":      {Data: []byte(`<form name="{{.Name}}" data-theme="` + dir + `">{{.ElementsHTML}}</form>`)},
+		dir + "/text.html": {Data: []byte(`<input type="text" name="{{.Name}}" value="{{.Value}}" data-theme="` + dir + `">`)},
+	}
+}
+
+func TestUseThemeFS(t *testing.T) {
+
+	form := Create("contact", "POST", "/contact")
+	form.UseThemeFS(mapTheme("themes/plain"), "themes/plain")
+	form.NewElement("text", "name", "Jane")
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<form name="contact" data-theme="themes/plain"><input type="text" name="name" value="Jane" data-theme="themes/plain"></form>`
+	if html != want {
+		t.Errorf("render\n%s\nwant\n%s", html, want)
+	}
+
+	// Changing the style leaves the file system theme
+	form.SetTemplateStyle("html")
+	if html, err := form.RenderString(); err != nil || strings.Contains(html, "data-theme") {
+		t.Errorf("html style render %q, error %v", html, err)
+	}
+}
+
+func TestUseThemeFSMissingTemplate(t *testing.T) {
+
+	form := Create("contact", "POST", "/contact")
+	form.UseThemeFS(mapTheme("plain"), "plain")
+	form.NewElement("text", "name", "")
+	form.NewElement("select", "city", "")
+
+	if _, err := form.RenderString(); err == nil {
+		t.Error("render without the select template, want error")
+	}
+	if _, err := form.ElementsHTML(); err == nil {
+		t.Error("elements without the select template, want error")
+	}
+
+	// Without the form template
+	form = Create("contact", "POST", "/contact")
+	form.UseThemeFS(mapTheme("plain"), "other")
+	if _, err := form.RenderString(); err == nil {
+		t.Error("render without the form template, want error")
+	}
+}
+
+func TestRegisterThemeFS(t *testing.T) {
+
+	fsys := mapTheme("registered_test")
+	RegisterThemeFS("registered_test", fsys)
+
+	form := Create("contact", "POST", "/contact")
+	form.SetTemplateStyle("registered_test")
+	form.NewElement("text", "name", "Jane")
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, `<form name="contact" data-theme="registered_test">`) || !strings.Contains(html, `value="Jane" data-theme="registered_test"`) {
+		t.Errorf("render without the registered theme:\n%s", html)
+	}
+
+	// The templates are parsed once
+	fsys["registered_test/text.html"] = &fstest.MapFile{Data: []byte(`changed`)}
+	if again, err := form.RenderString(); err != nil || again != html {
+		t.Errorf("second render %q, error %v, want the cached templates", again, err)
+	}
+
+	form.NewElement("textarea", "message", "")
+	if _, err := form.RenderString(); err == nil {
+		t.Error("render without the textarea template, want error")
+	}
+}
+
+func TestOwnThemeHasEveryType(t *testing.T) {
+
+	form := Create("own", "POST", "/")
+	form.SetOwnTemplateStyle("custom_templates")
+
+	for fieldType := range fieldTypes {
+		if _, err := form.template(fieldType); err != nil {
+			t.Errorf("%s: %v", fieldType, err)
+		}
+	}
+	if _, err := form.template("form"); err != nil {
+		t.Errorf("form: %v", err)
+	}
+}
+
+func TestOwnThemeRender(t *testing.T) {
+
+	form := testForm("bootstrap5")
+	form.SetOwnTemplateStyle("custom_templates")
+	form.NewElement("email", "mail", "")
+	form.NewElement("number", "quantity", "1")
+	form.NewElement("date", "day", "")
+	form.NewElement("multiselect", "colors", "")
+	form.SetOptions("colors", []OptionItem{{Key: "red", Value: "Red"}})
+	form.NewElement("checkboxgroup", "extras", "")
+	form.SetOptions("extras", []OptionItem{{Key: "gift", Value: "Gift wrap"}})
+	form.ShowIf("mail", "quantity", "notempty", "")
+
+	html, err := form.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"data-goform-conditions", "<script>"} {
+		if !strings.Contains(html, text) {
+			t.Errorf("render without %s", text)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package goform
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Rule structure, a validation rule attached to a form field.
+// Check returns true when the submitted value is valid.
+type Rule struct {
+	Name    string
+	Message string
+	Check   func(field Field, value string) bool
+}
+
+// Required rule, the value can not be empty.
+func Required(message string) Rule {
+	return Rule{"required", message, func(field Field, value string) bool {
+		return value != ""
+	}}
+}
+
+// MinLength rule, the value must have at least min characters.
+func MinLength(min int, message string) Rule {
+	return Rule{"minlength", message, func(field Field, value string) bool {
+		return utf8.RuneCountInString(value) >= min
+	}}
+}
+
+// MaxLength rule, the value must have at most max characters.
+func MaxLength(max int, message string) Rule {
+	return Rule{"maxlength", message, func(field Field, value string) bool {
+		return utf8.RuneCountInString(value) <= max
+	}}
+}
+
+// Pattern rule, the value must match the regular expression.
+// Panics if the expression can not be compiled, like regexp.MustCompile.
+func Pattern(expr string, message string) Rule {
+	re := regexp.MustCompile(expr)
+	return Rule{"pattern", message, func(field Field, value string) bool {
+		return re.MatchString(value)
+	}}
+}
+
+// Range rule, the value must be a number between min and max (both included).
+func Range(min float64, max float64, message string) Rule {
+	return Rule{"range", message, func(field Field, value string) bool {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		return number >= min && number <= max
+	}}
+}
+
+// OneOfOptions rule, the value must be the Key of one of the enabled field Options.
+func OneOfOptions(message string) Rule {
+	return Rule{"oneof", message, func(field Field, value string) bool {
+		for _, option := range field.Options {
+			if option.Key == value && !option.Disabled {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// AddRule adds a validation rule to the field.
+func (f *Form) AddRule(fieldName string, rule Rule) {
+	field := f.Elements[fieldName]
+	field.Rules = append(field.Rules, rule)
+	f.Elements[fieldName] = field
+}
+
+// Validate parse the submitted request and check the rules of every field.
+// Returns the list of errors, empty if the form is valid.
+func (f *Form) Validate(r *http.Request) []ErrorItem {
+
+	if err := f.parseRequest(r); err != nil {
+		return []ErrorItem{{RelatedTo: f.Name, Message: err.Error()}}
+	}
+
+	return f.validateValues(r.Context(), r.Form)
+}
+
+// ValidateValues check the rules of every field against the submitted values.
+// Only the first failing rule of each field is reported.
+func (f *Form) ValidateValues(values url.Values) []ErrorItem {
+	return f.validateValues(context.Background(), values)
+}
+
+// validateValues check the rules, ctx is passed to the option providers of the fields
+func (f *Form) validateValues(ctx context.Context, values url.Values) []ErrorItem {
+
+	errors := []ErrorItem{}
+
+	for _, field := range f.SortElements() {
+
+		// Hidden or disabled fields are not submitted
+		if !f.Active(field.Name, values) {
+			continue
+		}
+
+		// Repeaters check the number of rows and the rules of the group in each row
+		if field.FieldType == "repeater" {
+			errors = append(errors, validateRows(ctx, field, values)...)
+			continue
+		}
+
+		field, err := resolveOptions(ctx, field)
+		if err != nil {
+			errors = append(errors, ErrorItem{RelatedTo: field.Name, Message: err.Error(), Err: err})
+			continue
+		}
+
+		// Multi-valued fields check every submitted value
+		submitted := []string{values.Get(field.Name)}
+		if multiTypes[field.FieldType] && len(values[field.Name]) > 0 {
+			submitted = values[field.Name]
+		}
+
+		// Checkboxes are checked like Populate does, unchecked ones are empty
+		if field.FieldType == "checkbox" {
+			submitted = []string{""}
+			if checkboxChecked(field, values[field.Name]) {
+				submitted = []string{"on"}
+			}
+		}
+
+		// Signed fields check the verified value, altered values are errors
+		if field.FieldType == "signedhidden" {
+			value, err := f.openValue(field.Name, values.Get(field.Name))
+			if err != nil {
+				errors = append(errors, openError(field.Name, err))
+				continue
+			}
+			submitted = []string{value}
+		}
+
+	rules:
+		for _, rule := range field.Rules {
+			for _, value := range submitted {
+
+				// Empty values are only checked by the required rule
+				if value == "" && rule.Name != "required" {
+					continue
+				}
+
+				if !rule.Check(field, value) {
+					errors = append(errors, ErrorItem{RelatedTo: field.Name, Message: rule.Message})
+					break rules
+				}
+			}
+		}
+	}
+
+	return errors
+}
+
+// SetErrors set the error messages as help-text of the related fields.
+func (f *Form) SetErrors(errors []ErrorItem) {
+	for _, item := range errors {
+		if _, ok := f.Elements[item.RelatedTo]; ok {
+			f.SetHelpText(item.RelatedTo, item.Message)
+		}
+	}
+}
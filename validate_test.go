@@ -0,0 +1,135 @@
+package goform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// postValues returns a POST request with the values url-encoded in the body
+func postValues(target string, values url.Values) *http.Request {
+
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return r
+}
+
+func TestRules(t *testing.T) {
+
+	field := EmptyField()
+	field.Options = []OptionItem{{Key: "bcn", Value: "Barcelona"}, {Key: "mad", Value: "Madrid"}}
+
+	tests := []struct {
+		rule  Rule
+		value string
+		valid bool
+	}{
+		{Required("required"), "x", true},
+		{Required("required"), " ", true},
+		{Required("required"), "", false},
+		{MinLength(3, "minlength"), "abc", true},
+		{MinLength(3, "minlength"), "añb", true},
+		{MinLength(3, "minlength"), "ab", false},
+		{MaxLength(3, "maxlength"), "abc", true},
+		{MaxLength(3, "maxlength"), "ñññ", true},
+		{MaxLength(3, "maxlength"), "abcd", false},
+		{Pattern(`^[0-9]{5}$`, "pattern"), "08001", true},
+		{Pattern(`^[0-9]{5}$`, "pattern"), "0800", false},
+		{Pattern(`^[0-9]{5}$`, "pattern"), "08001x", false},
+		{Range(0, 100, "range"), "0", true},
+		{Range(0, 100, "range"), "100", true},
+		{Range(0, 100, "range"), "42.5", true},
+		{Range(0, 100, "range"), "-1", false},
+		{Range(0, 100, "range"), "100.01", false},
+		{Range(0, 100, "range"), "ten", false},
+		{OneOfOptions("oneof"), "bcn", true},
+		{OneOfOptions("oneof"), "Barcelona", false},
+		{OneOfOptions("oneof"), "vlc", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.Check(field, tt.value); got != tt.valid {
+			t.Errorf("%s(%q) = %v, want %v", tt.rule.Name, tt.value, got, tt.valid)
+		}
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+
+	form := Create("contact", "POST", "/contact")
+	form.NewElement("text", "name", "")
+	form.AddRule("name", Required("The name is required"))
+	form.AddRule("name", MaxLength(5, "The name is too long"))
+	form.NewElement("text", "zip", "")
+	form.AddRule("zip", Pattern(`^[0-9]{5}$`, "Invalid zip code"))
+	form.AddRule("zip", Range(1000, 52999, "Unknown zip code"))
+	form.NewElement("select", "city", "")
+	form.SetOptions("city", []OptionItem{{Key: "bcn", Value: "Barcelona"}})
+	form.AddRule("city", OneOfOptions("Choose a valid city"))
+
+	tests := []struct {
+		name   string
+		values url.Values
+		want   map[string]string
+	}{
+		{"valid", url.Values{"name": {"Jane"}, "zip": {"08001"}, "city": {"bcn"}}, map[string]string{}},
+		{"empty optional fields", url.Values{"name": {"Jane"}}, map[string]string{}},
+		{"missing required", url.Values{}, map[string]string{"name": "The name is required"}},
+		{"first failing rule", url.Values{"name": {"Jane Doe"}, "zip": {"0800"}}, map[string]string{"name": "The name is too long", "zip": "Invalid zip code"}},
+		{"second rule", url.Values{"name": {"Jane"}, "zip": {"00001"}}, map[string]string{"zip": "Unknown zip code"}},
+		{"unknown option", url.Values{"name": {"Jane"}, "city": {"vlc"}}, map[string]string{"city": "Choose a valid city"}},
+	}
+
+	for _, tt := range tests {
+
+		errors := form.ValidateValues(tt.values)
+
+		got := map[string]string{}
+		for _, item := range errors {
+			got[item.RelatedTo] = item.Message
+		}
+		if len(got) != len(errors) || len(got) != len(tt.want) {
+			t.Errorf("%s: errors %v, want %v", tt.name, errors, tt.want)
+			continue
+		}
+		for name, message := range tt.want {
+			if got[name] != message {
+				t.Errorf("%s: error of %s %q, want %q", tt.name, name, got[name], message)
+			}
+		}
+	}
+}
+
+func TestValidateCheckbox(t *testing.T) {
+
+	for _, value := range []string{"", "yes"} {
+
+		form := Create("signup", "POST", "/signup")
+		form.NewElement("checkbox", "terms", value)
+		form.AddRule("terms", Required("Accept the terms"))
+
+		if errors := form.ValidateValues(url.Values{"terms": {value}}); len(errors) != 0 {
+			t.Errorf("checkbox value %q checked: errors %v", value, errors)
+		}
+		if errors := form.ValidateValues(url.Values{}); len(errors) != 1 {
+			t.Errorf("checkbox value %q unchecked: errors %v, want the required error", value, errors)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+
+	form := Create("contact", "POST", "/contact")
+	form.NewElement("text", "email", "")
+	form.AddRule("email", Required("The email is required"))
+
+	if errors := form.Validate(postValues("/contact", url.Values{"email": {"jane@example.com"}})); len(errors) != 0 {
+		t.Errorf("valid request: errors %v", errors)
+	}
+	if errors := form.Validate(postValues("/contact", url.Values{})); len(errors) != 1 || errors[0].RelatedTo != "email" {
+		t.Errorf("invalid request: errors %v", errors)
+	}
+}
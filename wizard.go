@@ -0,0 +1,322 @@
+package goform
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Names of the hidden field and buttons of the wizard forms
+const (
+	stepStateField = "_goform_state"
+	stepIndexKey   = "_goform_step"
+	stepPrevButton = "_goform_prev"
+	stepNextButton = "_goform_next"
+)
+
+// ErrNoStepState the wizard form has neither secret nor store to keep its state
+var ErrNoStepState = errors.New("goform: wizard without secret or store")
+
+// ErrNoSteps the wizard form has no steps, see NewStep
+var ErrNoSteps = errors.New("goform: wizard without steps")
+
+// ErrInvalidStep the step of the wizard state is not a step of the form
+var ErrInvalidStep = errors.New("goform: invalid step")
+
+// Step structure, a page of a wizard form.
+type Step struct {
+	Name  string
+	Title string
+}
+
+// StepProgress structure, position of the rendered step used by the form templates.
+type StepProgress struct {
+	Number  int
+	Total   int
+	Name    string
+	Title   string
+	Percent int
+}
+
+// StepStore keeps the values submitted in the steps of a wizard between requests
+// (e.g. in the session), instead of the encrypted hidden field.
+type StepStore interface {
+	Load(r *http.Request) (url.Values, error)
+	Save(w http.ResponseWriter, r *http.Request, values url.Values) error
+}
+
+// WizardState structure, Step is the index of the step to render, Values holds the values
+// submitted in all the steps, Errors the validation errors of the submitted step and
+// Done is true when the last step was submitted and all the steps are valid.
+type WizardState struct {
+	Step   int
+	Values url.Values
+	Errors []ErrorItem
+	Done   bool
+	ctx    context.Context
+}
+
+// wizardConfig structure, configuration of the steps of the form
+type wizardConfig struct {
+	current string
+	secret  []byte
+	aead    cipher.AEAD
+	store   StepStore
+	labels  [3]string
+}
+
+// NewStep insert a new step, the elements created after it belong to the step.
+// Elements created before the first step are rendered in every step.
+func (f *Form) NewStep(stepName string, title string) {
+
+	f.Steps = append(f.Steps, Step{stepName, title})
+	f.wizard.current = stepName
+}
+
+// AddToStep move the field to the step.
+func (f *Form) AddToStep(fieldName string, step string) {
+	field := f.Elements[fieldName]
+	field.WizardStep = step
+	f.Elements[fieldName] = field
+}
+
+// SetStepSecret set the secret used to encrypt (AES-GCM) the hidden field with the values of
+// the previous steps, the user can neither read nor change them.
+func (f *Form) SetStepSecret(secret []byte) {
+
+	aead, err := newAEAD(deriveKey(secret, "goform wizard state"))
+	if err != nil {
+		f.errors = append(f.errors, ErrorItem{RelatedTo: "", Message: "Invalid Step Secret", Err: err})
+		return
+	}
+
+	f.wizard.secret = secret
+	f.wizard.aead = aead
+}
+
+// SetStepStore set the store of the values of the previous steps, replaces the encrypted hidden field.
+func (f *Form) SetStepStore(store StepStore) {
+	f.wizard.store = store
+}
+
+// SetStepButtons set the text of the previous, next and finish buttons of the steps.
+func (f *Form) SetStepButtons(prev string, next string, finish string) {
+	f.wizard.labels = [3]string{prev, next, finish}
+}
+
+// Wizard parse the submitted step and returns the step to render next. The values of the
+// submitted step are validated when moving forward, the wizard is Done when the last step
+// is submitted and the values of all the steps are valid.
+func (f *Form) Wizard(w http.ResponseWriter, r *http.Request) (*WizardState, error) {
+
+	if f.wizard.secret == nil && f.wizard.store == nil {
+		return nil, ErrNoStepState
+	}
+	if len(f.Steps) == 0 {
+		return nil, ErrNoSteps
+	}
+
+	if err := f.parseRequest(r); err != nil {
+		return nil, err
+	}
+
+	values, err := f.loadStepState(r)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &WizardState{Values: values, ctx: r.Context()}
+	state.Step, _ = strconv.Atoi(values.Get(stepIndexKey))
+	if state.Step < 0 || state.Step >= len(f.Steps) {
+		state.Step = 0
+	}
+
+	submitted := r.Form.Has(stepPrevButton) || r.Form.Has(stepNextButton)
+	if submitted {
+
+		// Replace the values of the submitted step
+		stepForm := f.stepForm(state.Step)
+		for key := range values {
+			if stepForm.ownsKey(key) {
+				values.Del(key)
+			}
+		}
+		for key, value := range r.Form {
+			if stepForm.ownsKey(key) {
+				values[key] = value
+			}
+		}
+
+		switch {
+		case r.Form.Has(stepPrevButton):
+			if state.Step > 0 {
+				state.Step--
+			}
+		default:
+			state.Errors = stepForm.validateValues(r.Context(), values)
+			if len(state.Errors) > 0 {
+				break
+			}
+			if state.Step < len(f.Steps)-1 {
+				state.Step++
+				break
+			}
+
+			// Last step, check all the steps again
+			state.Errors = f.validateValues(r.Context(), values)
+			state.Done = len(state.Errors) == 0
+			if !state.Done {
+				state.Step = f.firstStepWithErrors(state.Errors)
+			}
+		}
+	}
+
+	values.Set(stepIndexKey, strconv.Itoa(state.Step))
+
+	if f.wizard.store != nil {
+		if err := f.wizard.store.Save(w, r, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// RenderStep write the step of the state to w, with the values submitted so far, the
+// errors as help-text, the hidden state and the previous/next buttons.
+func (f *Form) RenderStep(w io.Writer, state *WizardState) error {
+
+	if f.wizard.secret == nil && f.wizard.store == nil {
+		return ErrNoStepState
+	}
+	if len(f.Steps) == 0 {
+		return ErrNoSteps
+	}
+	if state == nil || state.Step < 0 || state.Step >= len(f.Steps) {
+		return ErrInvalidStep
+	}
+
+	stepForm := f.stepForm(state.Step)
+	stepForm.Populate(state.Values)
+	stepForm.SetErrors(state.Errors)
+
+	if f.wizard.store == nil {
+		sealed, err := sealAEAD(f.wizard.aead, state.Values.Encode(), f.Name)
+		if err != nil {
+			return err
+		}
+		stepForm.NewElement("hidden", stepStateField, sealed)
+	}
+
+	labels := f.wizard.labels
+	if labels == [3]string{} {
+		labels = [3]string{"Previous", "Next", "Finish"}
+	}
+	if state.Step > 0 {
+		stepForm.NewElement("submit", stepPrevButton, labels[0])
+		stepForm.AddClass(stepPrevButton, "btn-secondary")
+	}
+	if state.Step < len(f.Steps)-1 {
+		stepForm.NewElement("submit", stepNextButton, labels[1])
+	} else {
+		stepForm.NewElement("submit", stepNextButton, labels[2])
+	}
+	stepForm.AddClass(stepNextButton, "btn-primary")
+
+	stepForm.progress = &StepProgress{
+		Number:  state.Step + 1,
+		Total:   len(f.Steps),
+		Name:    f.Steps[state.Step].Name,
+		Title:   f.Steps[state.Step].Title,
+		Percent: (state.Step + 1) * 100 / len(f.Steps),
+	}
+
+	ctx := state.ctx
+	if ctx == nil {
+		ctx = f.context()
+	}
+
+	return stepForm.RenderContext(ctx, w)
+}
+
+// CurrentStep returns the position of the rendered step, nil if the form is not rendered by RenderStep.
+func (f *Form) CurrentStep() *StepProgress {
+	return f.progress
+}
+
+// stepForm returns a copy of the form with only the elements of the step and the elements without step
+func (f *Form) stepForm(index int) *Form {
+
+	stepForm := *f
+	stepForm.Elements = map[string]Field{}
+	stepForm.FormTypes = map[string]int{}
+	stepForm.errors = nil
+
+	step := ""
+	if index >= 0 && index < len(f.Steps) {
+		step = f.Steps[index].Name
+	}
+
+	for name, field := range f.Elements {
+		if field.WizardStep == "" || field.WizardStep == step {
+			stepForm.Elements[name] = field
+			stepForm.FormTypes[field.FieldType]++
+		}
+	}
+
+	return &stepForm
+}
+
+// ownsKey returns true if the submitted key belongs to an element of the form (or its repeater rows)
+func (f *Form) ownsKey(key string) bool {
+
+	if _, ok := f.Elements[key]; ok {
+		return true
+	}
+	if name, _, ok := strings.Cut(key, "["); ok {
+		return f.Elements[name].FieldType == "repeater"
+	}
+
+	return false
+}
+
+// loadStepState returns the values of the previous steps from the store or the encrypted hidden field
+func (f *Form) loadStepState(r *http.Request) (url.Values, error) {
+
+	if f.wizard.store != nil {
+		values, err := f.wizard.store.Load(r)
+		if values == nil {
+			values = url.Values{}
+		}
+		return values, err
+	}
+
+	sealed := r.Form.Get(stepStateField)
+	if sealed == "" {
+		return url.Values{}, nil
+	}
+
+	text, err := openAEAD(f.wizard.aead, sealed, f.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return url.ParseQuery(text)
+}
+
+// firstStepWithErrors returns the index of the first step with an error
+func (f *Form) firstStepWithErrors(items []ErrorItem) int {
+	for index, step := range f.Steps {
+		for _, item := range items {
+			if f.Elements[item.RelatedTo].WizardStep == step.Name {
+				return index
+			}
+		}
+	}
+	return 0
+}
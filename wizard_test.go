@@ -0,0 +1,165 @@
+package goform
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// wizardForm returns a form with two steps
+func wizardForm() *Form {
+
+	form := Create("signup", "POST", "/signup")
+	form.SetStepSecret([]byte("wizard secret"))
+
+	form.NewStep("account", "Account")
+	form.NewElement("email", "email", "")
+	form.AddRule("email", Required("The email is required"))
+	form.NewElement("password", "password", "")
+
+	form.NewStep("profile", "Profile")
+	form.NewElement("text", "name", "")
+
+	return form
+}
+
+// postStep submit the values to the wizard
+func postStep(t *testing.T, form *Form, values url.Values) *WizardState {
+
+	t.Helper()
+
+	state, err := form.Wizard(httptest.NewRecorder(), postValues("/signup", values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// hiddenState returns the value of the hidden state field of the rendered step
+func hiddenState(t *testing.T, form *Form, state *WizardState) string {
+
+	t.Helper()
+
+	buf := new(strings.Builder)
+	if err := form.RenderStep(buf, state); err != nil {
+		t.Fatal(err)
+	}
+
+	match := regexp.MustCompile(`name="_goform_state"[^>]*value="([^"]+)"`).FindStringSubmatch(buf.String())
+	if match == nil {
+		t.Fatalf("step without state:\n%s", buf.String())
+	}
+
+	return match[1]
+}
+
+func TestWizardSteps(t *testing.T) {
+
+	form := wizardForm()
+
+	state := postStep(t, form, url.Values{})
+	if state.Step != 0 || state.Done {
+		t.Fatalf("first request: step %d, done %v", state.Step, state.Done)
+	}
+
+	state = postStep(t, form, url.Values{stepNextButton: {"Next"}, "email": {""}})
+	if state.Step != 0 || len(state.Errors) == 0 {
+		t.Fatalf("invalid step: step %d, errors %v", state.Step, state.Errors)
+	}
+
+	state = postStep(t, form, url.Values{stepNextButton: {"Next"}, "email": {"jane@example.com"}, "password": {"secret"}})
+	if state.Step != 1 {
+		t.Fatalf("valid step: step %d, errors %v", state.Step, state.Errors)
+	}
+	hidden := hiddenState(t, form, state)
+
+	state = postStep(t, form, url.Values{stepNextButton: {"Finish"}, stepStateField: {hidden}, "name": {"Jane"}})
+	if !state.Done {
+		t.Fatalf("last step: step %d, errors %v", state.Step, state.Errors)
+	}
+	if state.Values.Get("email") != "jane@example.com" || state.Values.Get("name") != "Jane" {
+		t.Errorf("values %v", state.Values)
+	}
+}
+
+func TestWizardTamperedState(t *testing.T) {
+
+	form := wizardForm()
+	state := postStep(t, form, url.Values{stepNextButton: {"Next"}, "email": {"jane@example.com"}})
+	hidden := hiddenState(t, form, state)
+
+	values := url.Values{stepNextButton: {"Finish"}, stepStateField: {hidden + "x"}}
+	r := postValues("/signup", values)
+
+	if _, err := form.Wizard(httptest.NewRecorder(), r); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("error %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestWizardWithoutSteps(t *testing.T) {
+
+	form := Create("signup", "POST", "/signup")
+	form.SetStepSecret([]byte("wizard secret"))
+	form.NewElement("text", "name", "")
+
+	r := httptest.NewRequest(http.MethodGet, "/signup", nil)
+	if _, err := form.Wizard(httptest.NewRecorder(), r); !errors.Is(err, ErrNoSteps) {
+		t.Errorf("Wizard error %v, want ErrNoSteps", err)
+	}
+	if err := form.RenderStep(new(strings.Builder), &WizardState{Values: url.Values{}}); !errors.Is(err, ErrNoSteps) {
+		t.Errorf("RenderStep error %v, want ErrNoSteps", err)
+	}
+}
+
+func TestWizardWithoutState(t *testing.T) {
+
+	form := Create("signup", "POST", "/signup")
+	form.NewStep("account", "Account")
+	form.NewElement("email", "email", "")
+
+	r := httptest.NewRequest(http.MethodGet, "/signup", nil)
+	if _, err := form.Wizard(httptest.NewRecorder(), r); !errors.Is(err, ErrNoStepState) {
+		t.Errorf("Wizard error %v, want ErrNoStepState", err)
+	}
+	if err := form.RenderStep(new(strings.Builder), &WizardState{Values: url.Values{}}); !errors.Is(err, ErrNoStepState) {
+		t.Errorf("RenderStep error %v, want ErrNoStepState", err)
+	}
+}
+
+func TestRenderStepOutOfRange(t *testing.T) {
+
+	form := wizardForm()
+
+	for _, state := range []*WizardState{nil, {Step: -1}, {Step: 2}} {
+		if err := form.RenderStep(new(strings.Builder), state); !errors.Is(err, ErrInvalidStep) {
+			t.Errorf("state %+v: error %v, want ErrInvalidStep", state, err)
+		}
+	}
+}
+
+func TestWizardStateEncrypted(t *testing.T) {
+
+	form := wizardForm()
+	state := postStep(t, form, url.Values{stepNextButton: {"Next"}, "email": {"jane@example.com"}, "password": {"hunter2-secret"}})
+	hidden := hiddenState(t, form, state)
+
+	text, _ := base64.RawURLEncoding.DecodeString(strings.Split(hidden, ".")[0])
+	if strings.Contains(hidden, "hunter2") || strings.Contains(string(text), "hunter2") {
+		t.Errorf("readable password in the hidden state %q", hidden)
+	}
+
+	// The state of other form is rejected
+	other := wizardForm()
+	other.Name = "other"
+	values := url.Values{stepNextButton: {"Finish"}, stepStateField: {hidden}}
+	r := postValues("/signup", values)
+	if _, err := other.Wizard(httptest.NewRecorder(), r); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("state of other form: error %v, want ErrInvalidSignature", err)
+	}
+}